@@ -1,17 +1,38 @@
 package api
 
 import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/api/handlers"
+	"github.com/sysdevguru/bluelabs/migrations"
 	"github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/idempotency"
+	"github.com/sysdevguru/bluelabs/usecase/outbox"
 	"github.com/sysdevguru/bluelabs/usecase/wallet"
+	"github.com/sysdevguru/bluelabs/usecase/webhook"
 
+	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 )
 
 type Service struct {
-	cfg      pkg.Config
-	db       *gorm.DB
-	walletUC *wallet.UseCase
+	cfg           pkg.Config
+	db            *gorm.DB
+	replica       *gorm.DB
+	walletUC      *wallet.UseCase
+	idempotencyUC *idempotency.UseCase
+	webhookUC     *webhook.UseCase
+	outboxUC      *outbox.UseCase
+	events        *pkg.EventBus
+	tasks         *pkg.TaskQueue
+	// executor serializes the changes of each wallet, it is nil unless
+	// enabled by the configuration.
+	executor *pkg.WalletExecutor
+
+	// openAPIValidator checks the traffic against the OpenAPI document,
+	// it is nil unless enabled by the configuration.
+	openAPIValidator mux.MiddlewareFunc
 }
 
 func NewService(cfg pkg.Config) (*Service, error) {
@@ -20,18 +41,175 @@ func NewService(cfg pkg.Config) (*Service, error) {
 		return nil, errors.Wrap(err, "failed to connect database")
 	}
 
+	migrator, err := pkg.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load migrations")
+	}
+	if err = migrator.Check(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "incompatible database schema, run the migrate command")
+	}
+
+	replica, err := pkg.NewGormWithPostgresReplica(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect read replica")
+	}
+
+	rates, err := newRateProvider(cfg.Rates)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load rates")
+	}
+
+	webhookUC := webhook.New(
+		pkg.NewWebhookRepo(db),
+		pkg.NewHTTPWebhookSender(),
+		webhook.Retry{
+			Base:     cfg.Webhooks.RetryBase,
+			Max:      cfg.Webhooks.RetryMax,
+			Attempts: cfg.Webhooks.MaxAttempts,
+		},
+		cfg.Webhooks.Timeout,
+	)
+
+	publisher, err := newEventPublisher(cfg.Outbox)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create outbox publisher")
+	}
+
+	// the webhooks are queued by the relay, so only committed changes are
+	// delivered and none is lost
+	outboxUC := outbox.New(
+		pkg.NewOutboxRepo(db),
+		outbox.Publishers{publisher, webhookUC},
+		cfg.Outbox.BatchSize,
+	)
+
+	events := pkg.NewEventBus()
+	tasks := pkg.NewTaskQueue(cfg.Tasks)
+
+	gormRepo, err := newRepo(db, cfg.Database)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create wallet repository")
+	}
+	if replica != nil {
+		gormRepo.WithReplica(replica, cfg.Database.ReplicaMaxStaleness)
+	}
+
+	var repo wallet.Repo = gormRepo
+	var executor *pkg.WalletExecutor
+	if cfg.Executor.Enabled {
+		executor = pkg.NewWalletExecutor(gormRepo, cfg.Executor)
+		repo = executor
+	}
+
 	walletUC := wallet.New(
 		"wallet_task",
-		pkg.NewRepo(db),
+		repo,
+		rates,
+		cfg.Rates.QuoteTTL,
+		events,
+		tasks,
+	)
+
+	idempotencyUC := idempotency.New(
+		cfg.Idempotency.Retention,
+		cfg.Idempotency.LockTimeout,
+		pkg.NewIdempotencyRepo(db),
 	)
 
+	var openAPIValidator mux.MiddlewareFunc
+	if cfg.Server.OpenAPIValidation {
+		doc, err := LoadOpenAPI()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load OpenAPI specification")
+		}
+
+		openAPIValidator, err = handlers.ValidateOpenAPI(doc)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create OpenAPI validation")
+		}
+	}
+
 	return &Service{
 		cfg,
 		db,
+		replica,
 		walletUC,
+		idempotencyUC,
+		webhookUC,
+		outboxUC,
+		events,
+		tasks,
+		executor,
+		openAPIValidator,
 	}, nil
 }
 
+// newRateProvider serves the rates of the rates file, or the static rates when there is none.
+func newRateProvider(cfg pkg.Rates) (wallet.RateProvider, error) {
+	if cfg.File != "" {
+		return pkg.NewFileRateProvider(cfg.File, cfg.ReloadInterval)
+	}
+
+	return pkg.NewStaticRateProvider(cfg.Base, cfg.Static)
+}
+
+// newRepo is the wallet repository with the locking and the transaction
+// retries of the configuration.
+func newRepo(db *gorm.DB, cfg pkg.Database) (*pkg.GormRepo, error) {
+	switch cfg.Locking {
+	case "pessimistic":
+		return pkg.NewRepo(db).WithTxRetries(cfg.TxRetries, cfg.TxBackoff), nil
+	case "optimistic":
+		return pkg.NewOptimisticRepo(db, cfg.OptimisticRetries).WithTxRetries(cfg.TxRetries, cfg.TxBackoff), nil
+	}
+
+	return nil, errors.Errorf("unknown locking %q", cfg.Locking)
+}
+
+// newEventPublisher is the publisher of the outbox events named by the configuration.
+func newEventPublisher(cfg pkg.Outbox) (outbox.EventPublisher, error) {
+	switch cfg.Publisher {
+	case "log":
+		return pkg.LogEventPublisher{}, nil
+	case "file":
+		return pkg.NewFileEventPublisher(cfg.File), nil
+	}
+
+	return nil, errors.Errorf("unknown publisher %q", cfg.Publisher)
+}
+
+// DeliverWebhooks sends the due webhook deliveries.
+func (s *Service) DeliverWebhooks(ctx context.Context) error {
+	return s.webhookUC.Deliver(ctx)
+}
+
+// RelayOutbox publishes the domain events saved in the outbox.
+func (s *Service) RelayOutbox(ctx context.Context) error {
+	return s.outboxUC.Drain(ctx)
+}
+
+// PurgeIdempotencyKeys removes the expired idempotency keys.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context) error {
+	return s.idempotencyUC.Purge(ctx)
+}
+
+// ReleaseExpiredHolds gives the funds of expired holds back to their wallets.
+func (s *Service) ReleaseExpiredHolds(ctx context.Context) error {
+	return s.walletUC.ReleaseExpiredHolds(ctx)
+}
+
+// Shutdown waits for the queued wallet tasks and closes the databases.
 func (s *Service) Shutdown() error {
+	s.tasks.Close()
+	if s.executor != nil {
+		s.executor.Stop()
+	}
+
+	if s.replica != nil {
+		if err := pkg.CloseDatabaseConnection(s.replica); err != nil {
+			return err
+		}
+	}
+
 	return pkg.CloseDatabaseConnection(s.db)
 }
@@ -26,6 +26,28 @@ var _ = Describe("Dependency", func() {
 			assert.NoError(GinkgoT(), err)
 		})
 
+		It("with unknown outbox publisher", func() {
+			os.Setenv("OUTBOX_PUBLISHER", "kafka")
+			defer os.Unsetenv("OUTBOX_PUBLISHER")
+
+			cfg, err := pkg.Load()
+			assert.NoError(GinkgoT(), err)
+
+			_, err = NewService(cfg)
+			assert.Error(GinkgoT(), err)
+		})
+
+		It("with unknown locking", func() {
+			os.Setenv("DATABASE_LOCKING", "none")
+			defer os.Unsetenv("DATABASE_LOCKING")
+
+			cfg, err := pkg.Load()
+			assert.NoError(GinkgoT(), err)
+
+			_, err = NewService(cfg)
+			assert.Error(GinkgoT(), err)
+		})
+
 		It("stop service", func() {
 			err := service.Shutdown()
 			assert.NoError(GinkgoT(), err)
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/sysdevguru/bluelabs/api/handlers"
+	"github.com/sysdevguru/bluelabs/api/walletpb"
+
+	"google.golang.org/grpc"
+)
+
+func NewGRPCServer(service *Service) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(handlers.UnaryServerInterceptor))
+	walletpb.RegisterWalletServiceServer(server, &handlers.GRPCHandler{
+		WalletUC: service.walletUC,
+	})
+
+	return server
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+func (handler *HTTPHandler) SetWalletStatus(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	request := model.WalletStatusRequest{}
+	if err = decode(r, &request, false); err != nil {
+		return err
+	}
+
+	wallet, err := handler.WalletUC.SetStatus(r.Context(), walletID, request.Status, request.Reason)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, wallet)
+}
+
+func (handler *HTTPHandler) ListWalletStatusChanges(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	changes, err := handler.WalletUC.ListStatusChanges(r.Context(), walletID)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, changes)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/idempotency"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+	"github.com/sysdevguru/bluelabs/usecase/webhook"
+)
+
+// errors of the request validation
+var (
+	errUserID           = errors.New("invalid user id")
+	errWalletID         = errors.New("invalid wallet id")
+	errHoldID           = errors.New("invalid hold id")
+	errQuoteID          = errors.New("invalid quote id")
+	errWebhookID        = errors.New("invalid webhook id")
+	errDeliveryID       = errors.New("invalid delivery id")
+	errInvalidAction    = errors.New("unavailable action")
+	errWalletFund       = errors.New("fund must be positive")
+	errWalletType       = errors.New("invalid wallet type")
+	errCloseReason      = errors.New("close reason is too long")
+	errCurrency         = errors.New("invalid currency")
+	errTransferAmount   = errors.New("transfer amount must be positive")
+	errHoldAmount       = errors.New("hold amount must be positive")
+	errInvalidLimit     = errors.New("invalid limit")
+	errInvalidFilter    = errors.New("invalid filter")
+	errInvalidBody      = errors.New("invalid request body")
+	errTrailingData     = errors.New("unexpected data after the request body")
+	errBodyTooLarge     = errors.New("request body too large")
+	errAmountTooLarge   = errors.New("amount exceeds the maximum")
+	errInvalidField     = errors.New("invalid value")
+	errRouteNotFound    = errors.New("route not found")
+	errMethodNotAllowed = errors.New("method not allowed")
+	errContractRequest  = errors.New("request does not match the API specification")
+	errLastEventID      = errors.New("invalid last event id")
+	errMaxStaleness     = errors.New("invalid max staleness")
+	errMinVersion       = errors.New("invalid min version")
+)
+
+type errorStatus struct {
+	status int
+	code   string
+}
+
+// errorStatuses maps the errors to their HTTP status and stable code,
+// errors which are not listed are server errors.
+var errorStatuses = map[error]errorStatus{
+	errUserID:           {http.StatusBadRequest, "INVALID_USER_ID"},
+	errWalletID:         {http.StatusBadRequest, "INVALID_WALLET_ID"},
+	errHoldID:           {http.StatusBadRequest, "INVALID_HOLD_ID"},
+	errQuoteID:          {http.StatusBadRequest, "INVALID_QUOTE_ID"},
+	errWebhookID:        {http.StatusBadRequest, "INVALID_WEBHOOK_ID"},
+	errDeliveryID:       {http.StatusBadRequest, "INVALID_DELIVERY_ID"},
+	errInvalidAction:    {http.StatusBadRequest, "INVALID_ACTION"},
+	errWalletFund:       {http.StatusBadRequest, "INVALID_FUND"},
+	errWalletType:       {http.StatusBadRequest, "INVALID_WALLET_TYPE"},
+	errCloseReason:      {http.StatusBadRequest, "INVALID_CLOSE_REASON"},
+	errCurrency:         {http.StatusBadRequest, "INVALID_CURRENCY"},
+	errTransferAmount:   {http.StatusBadRequest, "INVALID_TRANSFER_AMOUNT"},
+	errHoldAmount:       {http.StatusBadRequest, "INVALID_HOLD_AMOUNT"},
+	errInvalidLimit:     {http.StatusBadRequest, "INVALID_LIMIT"},
+	errInvalidFilter:    {http.StatusBadRequest, "INVALID_FILTER"},
+	errInvalidBody:      {http.StatusBadRequest, "INVALID_BODY"},
+	errBodyTooLarge:     {http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE"},
+	errAmountTooLarge:   {http.StatusBadRequest, "AMOUNT_TOO_LARGE"},
+	errInvalidField:     {http.StatusBadRequest, "INVALID_FIELD"},
+	errRouteNotFound:    {http.StatusNotFound, "ROUTE_NOT_FOUND"},
+	errMethodNotAllowed: {http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED"},
+	errContractRequest:  {http.StatusBadRequest, "INVALID_REQUEST"},
+	errLastEventID:      {http.StatusBadRequest, "INVALID_LAST_EVENT_ID"},
+	errMaxStaleness:     {http.StatusBadRequest, "INVALID_MAX_STALENESS"},
+	errMinVersion:       {http.StatusBadRequest, "INVALID_MIN_VERSION"},
+
+	wallet.ErrWalletNotFound:    {http.StatusNotFound, "WALLET_NOT_FOUND"},
+	wallet.ErrWalletExists:      {http.StatusConflict, "WALLET_EXISTS"},
+	wallet.ErrInsufficientFunds: {http.StatusBadRequest, "INSUFFICIENT_FUNDS"},
+	wallet.ErrCurrencyMismatch:  {http.StatusBadRequest, "CURRENCY_MISMATCH"},
+	wallet.ErrFundScale:         {http.StatusBadRequest, "INVALID_FUND_SCALE"},
+	wallet.ErrSameWallet:        {http.StatusBadRequest, "SAME_WALLET"},
+	wallet.ErrConcurrentUpdate:  {http.StatusConflict, "CONCURRENT_UPDATE"},
+	wallet.ErrVersionMismatch:   {http.StatusPreconditionFailed, "VERSION_MISMATCH"},
+	wallet.ErrWalletClosed:      {http.StatusGone, "WALLET_CLOSED"},
+	wallet.ErrWalletNotEmpty:    {http.StatusConflict, "WALLET_NOT_EMPTY"},
+	wallet.ErrWalletHeld:        {http.StatusConflict, "WALLET_HAS_HOLDS"},
+	wallet.ErrSweepWallet:       {http.StatusBadRequest, "INVALID_SWEEP_WALLET"},
+	wallet.ErrWalletFrozen:      {http.StatusForbidden, "WALLET_FROZEN"},
+	wallet.ErrWalletBlocked:     {http.StatusForbidden, "WALLET_WITHDRAW_BLOCKED"},
+	wallet.ErrWalletStatus:      {http.StatusBadRequest, "INVALID_WALLET_STATUS"},
+	wallet.ErrStatusTransition:  {http.StatusConflict, "STATUS_TRANSITION_NOT_ALLOWED"},
+	wallet.ErrStatusReason:      {http.StatusBadRequest, "INVALID_STATUS_REASON"},
+	wallet.ErrRateUnavailable:   {http.StatusBadRequest, "RATE_UNAVAILABLE"},
+	wallet.ErrRateProvider:      {http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE"},
+	wallet.ErrQuoteNotFound:     {http.StatusNotFound, "QUOTE_NOT_FOUND"},
+	wallet.ErrQuoteExpired:      {http.StatusConflict, "QUOTE_EXPIRED"},
+	wallet.ErrQuoteMismatch:     {http.StatusBadRequest, "QUOTE_MISMATCH"},
+	wallet.ErrQuoteCurrencies:   {http.StatusBadRequest, "INVALID_QUOTE_CURRENCIES"},
+	wallet.ErrConvertedAmount:   {http.StatusBadRequest, "CONVERTED_AMOUNT_TOO_SMALL"},
+	wallet.ErrHoldNotFound:      {http.StatusNotFound, "HOLD_NOT_FOUND"},
+	wallet.ErrHoldNotActive:     {http.StatusConflict, "HOLD_NOT_ACTIVE"},
+	wallet.ErrHoldExpired:       {http.StatusConflict, "HOLD_EXPIRED"},
+	wallet.ErrHoldTTL:           {http.StatusBadRequest, "INVALID_HOLD_TTL"},
+	wallet.ErrCaptureAmount:     {http.StatusBadRequest, "CAPTURE_EXCEEDS_HOLD"},
+	wallet.ErrInvalidCursor:     {http.StatusBadRequest, "INVALID_CURSOR"},
+	wallet.ErrQueueFull:         {http.StatusTooManyRequests, "QUEUE_FULL"},
+	wallet.ErrTaskTimeout:       {http.StatusServiceUnavailable, "TASK_TIMEOUT"},
+	wallet.ErrShuttingDown:      {http.StatusServiceUnavailable, "SHUTTING_DOWN"},
+	wallet.ErrJobNotFound:       {http.StatusNotFound, "JOB_NOT_FOUND"},
+
+	idempotency.ErrInvalidKey: {http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY"},
+	idempotency.ErrKeyReused:  {http.StatusConflict, "IDEMPOTENCY_KEY_REUSED"},
+	idempotency.ErrInProgress: {http.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS"},
+
+	webhook.ErrWebhookNotFound:  {http.StatusNotFound, "WEBHOOK_NOT_FOUND"},
+	webhook.ErrDeliveryNotFound: {http.StatusNotFound, "DELIVERY_NOT_FOUND"},
+	webhook.ErrWebhookURL:       {http.StatusBadRequest, "INVALID_WEBHOOK_URL"},
+	webhook.ErrWebhookEvents:    {http.StatusBadRequest, "INVALID_WEBHOOK_EVENTS"},
+	webhook.ErrDeliveryStatus:   {http.StatusBadRequest, "INVALID_DELIVERY_STATUS"},
+}
+
+// statusError returns the HTTP error of err. The first error of the chain
+// found in errorStatuses decides the status, unknown errors are server errors.
+func statusError(err error) pkg.StatusError {
+	statusErr := pkg.StatusError{}
+	if errors.As(err, &statusErr) {
+		return statusErr
+	}
+
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		if status, ok := errorStatuses[cause]; ok {
+			return pkg.StatusError{
+				Code:    status.status,
+				ErrMsg:  cause.Error(),
+				ErrCode: status.code,
+			}
+		}
+	}
+
+	return pkg.StatusError{
+		Code:   http.StatusInternalServerError,
+		ErrMsg: err.Error(),
+	}
+}
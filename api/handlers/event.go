@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// LastEventIDHeader carries the id of the last event a reconnecting
+	// client received, browsers send it on their own.
+	LastEventIDHeader = "Last-Event-ID"
+
+	// lastEventIDParam replaces LastEventIDHeader for WebSocket clients,
+	// which cannot set headers.
+	lastEventIDParam = "last_event_id"
+
+	// streamWriteTimeout bounds the write of a single event.
+	streamWriteTimeout = 10 * time.Second
+)
+
+// errStreamLagged ends a stream whose client does not keep up.
+var errStreamLagged = errors.New("subscriber too slow")
+
+var upgrader = websocket.Upgrader{
+	HandshakeTimeout: streamWriteTimeout,
+	Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+		writeProblem(w, r, pkg.StatusError{Code: status, ErrMsg: reason.Error()})
+	},
+}
+
+// eventStream sends balance events to a connected client.
+type eventStream interface {
+	Send(event model.BalanceEvent) error
+	Heartbeat() error
+	// Done is closed when the client goes away.
+	Done() <-chan struct{}
+	// Close ends the stream, err tells why.
+	Close(err error)
+}
+
+// StreamEvents pushes the balance events of a wallet over Server-Sent
+// Events, or over a WebSocket when the request asks for an upgrade.
+// Clients reconnect with the id of the last event they received and
+// get the events they missed first.
+func (handler *HTTPHandler) StreamEvents(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	lastID, catchUp, err := lastEventID(r)
+	if err != nil {
+		return err
+	}
+
+	if _, err = handler.WalletUC.GetWallet(r.Context(), userID, walletID); err != nil {
+		return err
+	}
+
+	// subscribe before catching up so no event falls in between
+	subscription := handler.Events.Subscribe(walletID, handler.EventsCfg.Buffer)
+	defer subscription.Close()
+
+	var stream eventStream
+	if websocket.IsWebSocketUpgrade(r) {
+		conn, err := upgrader.Upgrade(w, r, http.Header{
+			RequestIDHeader: w.Header().Values(RequestIDHeader),
+		})
+		if err != nil {
+			// the upgrader has answered the request
+			return nil
+		}
+		stream = newWebSocketStream(conn)
+	} else {
+		stream, err = openServerSentEvents(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = handler.stream(r.Context(), stream, subscription, userID, walletID, lastID, catchUp)
+	stream.Close(err)
+	if err != nil && !errors.Is(err, errStreamLagged) {
+		log.Printf("request %s stream failed: %s\n", requestID(r.Context()), err.Error())
+	}
+
+	return nil
+}
+
+// stream sends the events after lastID until the client goes away,
+// the missed events are read from the ledger first when catchUp is set.
+func (handler *HTTPHandler) stream(
+	ctx context.Context,
+	stream eventStream,
+	subscription *pkg.Subscription,
+	userID, walletID, lastID int64,
+	catchUp bool,
+) error {
+	// the events sent while catching up, which the subscription may deliver
+	// again; the events it delivers out of order are still sent
+	sent := map[int64]bool{}
+	for catchUp {
+		events, err := handler.WalletUC.Changes(ctx, userID, walletID, lastID)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err = stream.Send(event); err != nil {
+				return err
+			}
+			sent[event.ID] = true
+			lastID = event.ID
+		}
+
+		catchUp = len(events) == wallet.MaxPageSize
+	}
+
+	heartbeat := time.NewTicker(handler.EventsCfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-stream.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := stream.Heartbeat(); err != nil {
+				return err
+			}
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return errStreamLagged
+			}
+
+			if sent[event.ID] {
+				delete(sent, event.ID)
+				continue
+			}
+
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lastEventID is the id of the last event the client received,
+// ok is false for a new client.
+func lastEventID(r *http.Request) (id int64, ok bool, err error) {
+	value := r.Header.Get(LastEventIDHeader)
+	if value == "" {
+		value = r.URL.Query().Get(lastEventIDParam)
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+
+	id, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, errLastEventID
+	}
+
+	return id, true, nil
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+
+	"github.com/gorilla/websocket"
+)
+
+// errStreamUnsupported is returned when the connection cannot be taken over.
+var errStreamUnsupported = errors.New("streaming is not supported")
+
+// serverSentEvents is an event stream of text/event-stream messages.
+type serverSentEvents struct {
+	conn   net.Conn
+	writer *bufio.Writer
+	done   chan struct{}
+}
+
+// openServerSentEvents takes over the connection of w and starts the
+// response. The stream outlives the timeouts of the server, which are
+// replaced by a timeout per write.
+func openServerSentEvents(w http.ResponseWriter) (*serverSentEvents, error) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errStreamUnsupported
+	}
+
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	header := w.Header().Clone()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "close")
+
+	stream := &serverSentEvents{
+		conn:   conn,
+		writer: buffer.Writer,
+		done:   make(chan struct{}),
+	}
+
+	// the client sends nothing more, reading only notices it going away
+	go func() {
+		_, _ = io.Copy(io.Discard, buffer.Reader)
+		close(stream.done)
+	}()
+
+	err = stream.write(func() error {
+		if _, err := io.WriteString(stream.writer, "HTTP/1.1 200 OK\r\n"); err != nil {
+			return err
+		}
+		if err := header.Write(stream.writer); err != nil {
+			return err
+		}
+
+		_, err := io.WriteString(stream.writer, "\r\n")
+		return err
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (s *serverSentEvents) Send(event model.BalanceEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.write(func() error {
+		_, err := fmt.Fprintf(s.writer, "id: %d\nevent: balance\ndata: %s\n\n", event.ID, data)
+		return err
+	})
+}
+
+func (s *serverSentEvents) Heartbeat() error {
+	return s.write(func() error {
+		_, err := io.WriteString(s.writer, ": heartbeat\n\n")
+		return err
+	})
+}
+
+func (s *serverSentEvents) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close ends the response, clients reconnect with the last event id.
+func (s *serverSentEvents) Close(err error) {
+	s.conn.Close()
+}
+
+func (s *serverSentEvents) write(fn func() error) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return s.writer.Flush()
+}
+
+// webSocketStream is an event stream of JSON WebSocket messages.
+type webSocketStream struct {
+	conn *websocket.Conn
+	done chan struct{}
+}
+
+func newWebSocketStream(conn *websocket.Conn) *webSocketStream {
+	stream := &webSocketStream{
+		conn: conn,
+		done: make(chan struct{}),
+	}
+
+	// reading handles the control messages and notices the client going away
+	go func() {
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				close(stream.done)
+				return
+			}
+		}
+	}()
+
+	return stream
+}
+
+func (s *webSocketStream) Send(event model.BalanceEvent) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+
+	return s.conn.WriteJSON(event)
+}
+
+func (s *webSocketStream) Heartbeat() error {
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+}
+
+func (s *webSocketStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close sends the reason of the end of the stream before closing it.
+func (s *webSocketStream) Close(err error) {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if errors.Is(err, errStreamLagged) {
+		message = websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error())
+	} else if err != nil {
+		message = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "")
+	}
+
+	_ = s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(streamWriteTimeout))
+	s.conn.Close()
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/api/walletpb"
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var walletTypes = map[walletpb.WalletType]model.WalletType{
+	walletpb.WalletType_WALLET_TYPE_UNSPECIFIED:     "",
+	walletpb.WalletType_WALLET_TYPE_CASH:            model.WalletCash,
+	walletpb.WalletType_WALLET_TYPE_BONUS:           model.WalletBonus,
+	walletpb.WalletType_WALLET_TYPE_LOCKED_WINNINGS: model.WalletLockedWinnings,
+}
+
+var walletTypeMessages = map[model.WalletType]walletpb.WalletType{
+	model.WalletCash:           walletpb.WalletType_WALLET_TYPE_CASH,
+	model.WalletBonus:          walletpb.WalletType_WALLET_TYPE_BONUS,
+	model.WalletLockedWinnings: walletpb.WalletType_WALLET_TYPE_LOCKED_WINNINGS,
+}
+
+var walletStatuses = map[model.WalletStatus]walletpb.WalletStatus{
+	model.WalletActive:          walletpb.WalletStatus_WALLET_STATUS_ACTIVE,
+	model.WalletWithdrawBlocked: walletpb.WalletStatus_WALLET_STATUS_WITHDRAW_BLOCKED,
+	model.WalletFrozen:          walletpb.WalletStatus_WALLET_STATUS_FROZEN,
+	model.WalletClosed:          walletpb.WalletStatus_WALLET_STATUS_CLOSED,
+}
+
+// GRPCHandler serves the wallet use case over gRPC. The requests are
+// validated like their HTTP counterparts and fail with the same errors.
+type GRPCHandler struct {
+	walletpb.UnimplementedWalletServiceServer
+
+	WalletUC *wallet.UseCase
+}
+
+func (handler *GRPCHandler) Create(ctx context.Context, in *walletpb.CreateRequest) (*walletpb.Wallet, error) {
+	walletType, ok := walletTypes[in.Type]
+	if !ok {
+		return nil, invalidField("type", errWalletType)
+	}
+
+	request := model.CreateWalletRequest{
+		Type:     walletType,
+		Currency: model.Currency(in.Currency),
+	}
+	if err := validateRequest(&request); err != nil {
+		return nil, err
+	}
+
+	created, err := handler.WalletUC.Create(ctx, in.UserId, request.Type, request.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return walletMessage(created), nil
+}
+
+func (handler *GRPCHandler) GetWallet(ctx context.Context, in *walletpb.GetWalletRequest) (*walletpb.Wallet, error) {
+	found, err := handler.WalletUC.GetWallet(ctx, in.UserId, in.WalletId)
+	if err != nil {
+		return nil, err
+	}
+
+	return walletMessage(found), nil
+}
+
+func (handler *GRPCHandler) Deposit(ctx context.Context, in *walletpb.TransactionRequest) (*walletpb.Wallet, error) {
+	transaction, err := transactionRequest(in, model.ActionDeposit)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := handler.WalletUC.Deposit(ctx, in.UserId, in.WalletId, transaction.Fund, transaction.Currency, ifVersion(in))
+	if err != nil {
+		return nil, err
+	}
+
+	return walletMessage(updated), nil
+}
+
+func (handler *GRPCHandler) Withdraw(ctx context.Context, in *walletpb.TransactionRequest) (*walletpb.Wallet, error) {
+	transaction, err := transactionRequest(in, model.ActionWithdraw)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := handler.WalletUC.Withdraw(ctx, in.UserId, in.WalletId, transaction.Fund, transaction.Currency, ifVersion(in))
+	if err != nil {
+		return nil, err
+	}
+
+	return walletMessage(updated), nil
+}
+
+// ifVersion is the version a change of in is conditional on, like the
+// If-Match header, nil for an unconditional change.
+func ifVersion(in *walletpb.TransactionRequest) []int64 {
+	if in.IfVersion == 0 {
+		return nil
+	}
+
+	return []int64{in.IfVersion}
+}
+
+// transactionRequest validates in as the body of a balance change.
+func transactionRequest(in *walletpb.TransactionRequest, action model.ActionValue) (*model.Transaction, error) {
+	fund, err := model.NewMoney(in.Fund)
+	if err != nil {
+		return nil, invalidField("fund", errWalletFund)
+	}
+
+	transaction := model.Transaction{
+		Action:   action,
+		Fund:     fund,
+		Currency: model.Currency(in.Currency),
+	}
+	if err = validateRequest(&transaction); err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func walletMessage(w *model.Wallet) *walletpb.Wallet {
+	message := &walletpb.Wallet{
+		Id:           w.ID,
+		UserId:       w.UserID,
+		Type:         walletTypeMessages[w.Type],
+		Currency:     string(w.Currency),
+		Balance:      w.Balance.String(),
+		Held:         w.Held.String(),
+		Available:    w.Available().String(),
+		Status:       walletStatuses[w.Status],
+		StatusReason: w.StatusReason,
+		CloseReason:  w.CloseReason,
+		Version:      w.Version,
+	}
+
+	if w.ClosedAt != nil {
+		message.ClosedAt = timestamppb.New(*w.ClosedAt)
+	}
+
+	return message
+}
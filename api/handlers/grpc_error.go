@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/sysdevguru/bluelabs/pkg"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo of the gRPC errors.
+const ErrorDomain = "bluelabs"
+
+// requestIDMetadata carries the id of a gRPC call, like RequestIDHeader.
+const requestIDMetadata = "x-request-id"
+
+// grpcCodes maps the HTTP statuses of the errors to gRPC codes.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusForbidden:             codes.FailedPrecondition,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.FailedPrecondition,
+	http.StatusGone:                  codes.FailedPrecondition,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+}
+
+// grpcErrorCodes are the errors whose gRPC code differs from the one of
+// their HTTP status.
+var grpcErrorCodes = map[string]codes.Code{
+	"WALLET_EXISTS":      codes.AlreadyExists,
+	"CONCURRENT_UPDATE":  codes.Aborted,
+	"INSUFFICIENT_FUNDS": codes.FailedPrecondition,
+	"TASK_TIMEOUT":       codes.DeadlineExceeded,
+}
+
+// UnaryServerInterceptor gives every call a request id, which is returned
+// in the x-request-id header, and turns the errors of the handlers into
+// gRPC statuses with the stable code of the error.
+func UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(requestIDMetadata)) > 0 {
+		id = md.Get(requestIDMetadata)[0]
+	}
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id)); err != nil {
+		log.Println("failed to set request id header", err)
+	}
+
+	resp, err := handler(context.WithValue(ctx, requestIDKey{}, id), req)
+	if err != nil {
+		return nil, grpcStatus(id, err).Err()
+	}
+
+	return resp, nil
+}
+
+// grpcStatus returns the gRPC status of err. The messages of server errors
+// are logged instead of being returned to the client.
+func grpcStatus(id string, err error) *status.Status {
+	if _, ok := status.FromError(err); ok {
+		return status.Convert(err)
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	}
+
+	statusErr := statusError(err)
+	code, ok := grpcErrorCodes[statusErr.ErrorCode()]
+	if !ok {
+		code, ok = grpcCodes[statusErr.Status()]
+	}
+	if !ok {
+		code = codes.Internal
+	}
+
+	message := statusErr.Error()
+	if code == codes.Internal {
+		log.Printf("request %s failed: %s\n", id, message)
+		message = http.StatusText(http.StatusInternalServerError)
+	}
+
+	st := status.New(code, message)
+	withDetails, detailsErr := st.WithDetails(errorDetails(id, statusErr)...)
+	if detailsErr != nil {
+		log.Println("failed to add error details", detailsErr)
+		return st
+	}
+
+	return withDetails
+}
+
+// errorDetails are the stable code and request id of err, and the fields
+// which failed validation.
+func errorDetails(id string, err pkg.StatusError) []proto.Message {
+	details := []proto.Message{
+		&errdetails.ErrorInfo{
+			Reason:   err.ErrorCode(),
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{"request_id": id},
+		},
+	}
+
+	if len(err.Fields) == 0 {
+		return details
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, field := range err.Fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Message,
+		})
+	}
+
+	return append(details, badRequest)
+}
@@ -2,27 +2,145 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/sysdevguru/bluelabs/model"
 	"github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/idempotency"
 	"github.com/sysdevguru/bluelabs/usecase/wallet"
+	"github.com/sysdevguru/bluelabs/usecase/webhook"
+
+	"github.com/gorilla/mux"
 )
 
+// unknownFieldPrefix starts the decoding errors of unknown fields,
+// encoding/json has no type for them.
+const unknownFieldPrefix = "json: unknown field "
+
 type HTTPHandler struct {
-	Handle   func(w http.ResponseWriter, r *http.Request) error
-	WalletUC *wallet.UseCase
+	Handle        func(w http.ResponseWriter, r *http.Request) error
+	WalletUC      *wallet.UseCase
+	IdempotencyUC *idempotency.UseCase
+	WebhookUC     *webhook.UseCase
+	Events        *pkg.EventBus
+	EventsCfg     pkg.Events
 }
 
-// ServeHTTP allows custom handler to satisfy http.Handler
+// ServeHTTP allows custom handler to satisfy http.Handler.
+// Errors are written as problem details, unknown errors as 500.
 func (h HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	err := h.Handle(w, r)
-	if err != nil {
-		switch e := err.(type) {
-		case pkg.StatusError:
-			http.Error(w, e.Error(), e.Status())
-		default:
+	if err == nil {
+		return
+	}
+
+	writeProblem(w, r, statusError(err))
+}
+
+// NotFound answers the requests of unknown routes.
+func NotFound(w http.ResponseWriter, r *http.Request) error {
+	return errRouteNotFound
+}
+
+// MethodNotAllowed answers the requests of known routes with another method.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) error {
+	return errMethodNotAllowed
+}
+
+// writeProblem writes err as an RFC 7807 problem.
+func writeProblem(w http.ResponseWriter, r *http.Request, err pkg.StatusError) {
+	problem := newProblem(r, err)
+	buffer, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		log.Println("failed to encode problem", marshalErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	if _, writeErr := w.Write(buffer); writeErr != nil {
+		log.Println("failed to write problem", writeErr)
+	}
+}
+
+// newProblem is the RFC 7807 problem of err. The messages of server
+// errors are logged instead of being returned to the client.
+func newProblem(r *http.Request, err pkg.StatusError) pkg.Problem {
+	problem := pkg.Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(err.Status()),
+		Status:    err.Status(),
+		Detail:    err.Error(),
+		Code:      err.ErrorCode(),
+		RequestID: requestID(r.Context()),
+		Errors:    err.Fields,
+	}
+
+	if err.Status() >= http.StatusInternalServerError {
+		log.Printf("request %s failed: %s\n", problem.RequestID, err.Error())
+		problem.Detail = http.StatusText(err.Status())
+	}
+
+	return problem
+}
+
+// invalidField is the error of a request field which failed validation.
+func invalidField(field string, err error) error {
+	statusErr := statusError(err)
+	statusErr.Fields = []pkg.FieldError{
+		{
+			Field:   field,
+			Code:    statusErr.ErrorCode(),
+			Message: statusErr.ErrMsg,
+		},
+	}
+
+	return statusErr
+}
+
+// invalidBody is the error of a request body which cannot be decoded,
+// the field is given when a value has the wrong type or is unknown.
+func invalidBody(err error) error {
+	code := errorStatuses[errInvalidBody].code
+	statusErr := pkg.StatusError{
+		Code:    http.StatusBadRequest,
+		ErrMsg:  err.Error(),
+		ErrCode: code,
+	}
+
+	field := ""
+	typeErr := &json.UnmarshalTypeError{}
+	if errors.As(err, &typeErr) {
+		field = typeErr.Field
+	} else if strings.HasPrefix(err.Error(), unknownFieldPrefix) {
+		field = strings.Trim(strings.TrimPrefix(err.Error(), unknownFieldPrefix), `"`)
+	}
+
+	if field != "" {
+		statusErr.Fields = []pkg.FieldError{
+			{
+				Field:   field,
+				Code:    code,
+				Message: err.Error(),
+			},
 		}
 	}
+
+	return statusErr
+}
+
+// pathID parses the numeric path param key, invalid is returned when it is invalid.
+func pathID(r *http.Request, key string, invalid error) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)[key], 10, 64)
+	if err != nil {
+		return 0, invalid
+	}
+
+	return id, nil
 }
 
 func renderJSON(w http.ResponseWriter, value interface{}) error {
@@ -31,9 +149,38 @@ func renderJSON(w http.ResponseWriter, value interface{}) error {
 		return err
 	}
 
+	return writeJSON(w, http.StatusOK, buffer)
+}
+
+// renderResult writes value with its resultStatus.
+func renderResult(w http.ResponseWriter, value interface{}) error {
+	buffer, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(w, resultStatus(w, value), buffer)
+}
+
+// resultStatus is the status of a response with value. A queued job is
+// accepted and located at its resource, a wallet is tagged with its version.
+func resultStatus(w http.ResponseWriter, value interface{}) int {
+	switch value := value.(type) {
+	case *model.Job:
+		w.Header().Set("Location", jobLocation(value.ID))
+		return http.StatusAccepted
+	case *model.Wallet:
+		w.Header().Set("ETag", walletETag(value.Version))
+	}
+
+	return http.StatusOK
+}
+
+func writeJSON(w http.ResponseWriter, status int, buffer []byte) error {
 	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(status)
 
-	_, err = w.Write(buffer)
+	_, err := w.Write(buffer)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/usecase/idempotency"
+)
+
+func (handler *HTTPHandler) CreateHold(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	request := model.HoldRequest{}
+	if err = decode(r, &request, false); err != nil {
+		return err
+	}
+
+	fingerprint := idempotency.Fingerprint(
+		r.Method,
+		r.URL.Path,
+		request.Amount.String(),
+		strconv.Itoa(request.TTLSeconds),
+	)
+
+	return handler.idempotent(w, r, fingerprint, func() (interface{}, error) {
+		ttl := time.Duration(request.TTLSeconds) * time.Second
+		return handler.WalletUC.Hold(r.Context(), userID, walletID, request.Amount, ttl)
+	})
+}
+
+func (handler *HTTPHandler) GetHold(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	holdID, err := pathID(r, "holdId", errHoldID)
+	if err != nil {
+		return err
+	}
+
+	hold, err := handler.WalletUC.GetHold(r.Context(), userID, walletID, holdID)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, hold)
+}
+
+func (handler *HTTPHandler) CaptureHold(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	holdID, err := pathID(r, "holdId", errHoldID)
+	if err != nil {
+		return err
+	}
+
+	// an empty body captures the whole hold
+	request := model.CaptureRequest{}
+	if err = decode(r, &request, true); err != nil {
+		return err
+	}
+
+	// omitempty skips an explicit zero amount
+	captured := ""
+	if request.Amount != nil {
+		if request.Amount.IsZero() {
+			return invalidField("amount", errHoldAmount)
+		}
+
+		captured = request.Amount.String()
+	}
+
+	fingerprint := idempotency.Fingerprint(
+		r.Method,
+		r.URL.Path,
+		captured,
+	)
+
+	return handler.idempotent(w, r, fingerprint, func() (interface{}, error) {
+		return handler.WalletUC.Capture(r.Context(), userID, walletID, holdID, request.Amount)
+	})
+}
+
+func (handler *HTTPHandler) ReleaseHold(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	holdID, err := pathID(r, "holdId", errHoldID)
+	if err != nil {
+		return err
+	}
+
+	hold, err := handler.WalletUC.Release(r.Context(), userID, walletID, holdID)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, hold)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+)
+
+// idempotencyTimeout bounds storing the outcome of a request, which must
+// happen even when its client is gone.
+const idempotencyTimeout = 5 * time.Second
+
+// idempotent runs do once per Idempotency-Key and replays its stored
+// response when the request is retried with the same key.
+// Requests without the header are always processed. The key of a request
+// which failed is released for a retry only when the change surely did not
+// commit, otherwise it is held until the reservation times out.
+func (handler *HTTPHandler) idempotent(
+	w http.ResponseWriter,
+	r *http.Request,
+	fingerprint string,
+	do func() (interface{}, error),
+) error {
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		value, err := do()
+		if err != nil {
+			return err
+		}
+
+		return renderResult(w, value)
+	}
+
+	record, err := handler.IdempotencyUC.Begin(r.Context(), key, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	if record.Completed() {
+		w.Header().Set("Idempotent-Replayed", "true")
+		replayHeaders(w, record.StatusCode, record.Response)
+		return writeJSON(w, record.StatusCode, record.Response)
+	}
+
+	value, err := do()
+	if err != nil {
+		if uncommitted(err) {
+			ctx, cancel := context.WithTimeout(context.Background(), idempotencyTimeout)
+			defer cancel()
+
+			if releaseErr := handler.IdempotencyUC.Release(ctx, record); releaseErr != nil {
+				log.Println("failed to release idempotency key", releaseErr)
+			}
+		}
+
+		return err
+	}
+
+	buffer, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	// the request is already applied, so a failure here must not fail it
+	status := resultStatus(w, value)
+	ctx, cancel := context.WithTimeout(context.Background(), idempotencyTimeout)
+	defer cancel()
+
+	if err = handler.IdempotencyUC.Complete(ctx, record, status, buffer); err != nil {
+		log.Println("failed to store idempotent response", err)
+	}
+
+	return writeJSON(w, status, buffer)
+}
+
+// uncommitted tells whether a request failing with err surely made no
+// change. A request which timed out or failed on an unexpected error may
+// still commit, as the executor may run it after its caller is gone.
+func uncommitted(err error) bool {
+	return errors.Is(err, wallet.ErrShuttingDown) ||
+		statusError(err).Status() < http.StatusInternalServerError
+}
+
+// replayHeaders sets the headers resultStatus set on the stored response.
+func replayHeaders(w http.ResponseWriter, status int, response []byte) {
+	if status == http.StatusAccepted {
+		job := model.Job{}
+		if err := json.Unmarshal(response, &job); err == nil {
+			w.Header().Set("Location", jobLocation(job.ID))
+		}
+		return
+	}
+
+	wallet := model.Wallet{}
+	if err := json.Unmarshal(response, &wallet); err == nil && wallet.Version > 0 {
+		w.Header().Set("ETag", walletETag(wallet.Version))
+	}
+}
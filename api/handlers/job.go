@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/pkg"
+
+	"github.com/gorilla/mux"
+)
+
+// jobResponse is a job with the problem of its error when it failed.
+type jobResponse struct {
+	*model.Job
+	Error *pkg.Problem `json:"error,omitempty"`
+}
+
+func (handler *HTTPHandler) GetJob(w http.ResponseWriter, r *http.Request) error {
+	job, err := handler.WalletUC.Job(r.Context(), mux.Vars(r)["jobId"])
+	if err != nil {
+		return err
+	}
+
+	response := jobResponse{Job: job}
+	if job.Err != nil {
+		problem := newProblem(r, statusError(job.Err))
+		response.Error = &problem
+	}
+
+	return renderJSON(w, response)
+}
+
+// preferAsync tells whether the request prefers to be answered before it
+// is processed (RFC 7240), the preference is then applied.
+func preferAsync(w http.ResponseWriter, r *http.Request) bool {
+	for _, preferences := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(preferences, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "respond-async") {
+				w.Header().Set("Preference-Applied", "respond-async")
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func jobLocation(id string) string {
+	return "/jobs/" + id
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/sysdevguru/bluelabs/pkg"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+// OpenAPI serves the OpenAPI document of the API.
+func OpenAPI(spec []byte) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		return writeJSON(w, http.StatusOK, spec)
+	}
+}
+
+// ErrorCodes returns the codes of every error the API answers with.
+func ErrorCodes() []string {
+	seen := map[string]bool{
+		pkg.StatusCode(http.StatusInternalServerError): true,
+	}
+	for _, status := range errorStatuses {
+		seen[status.code] = true
+	}
+
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes
+}
+
+// ValidateOpenAPI checks the requests and responses of the routes against
+// doc. Requests which do not match are rejected, responses which do not
+// match are logged and replaced by a server error. It buffers every
+// response, so it is meant for development and test environments.
+func ValidateOpenAPI(doc *openapi3.T) (mux.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				writeProblem(w, r, statusError(routeError(err)))
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err = openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				statusErr := statusError(errContractRequest)
+				statusErr.ErrMsg = fmt.Sprintf("%s: %s", statusErr.ErrMsg, err.Error())
+				writeProblem(w, r, statusErr)
+				return
+			}
+
+			// streams are not buffered, their events are not checked
+			if streamResponse(route.Operation) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buffer := newResponseBuffer()
+			next.ServeHTTP(buffer, r)
+
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 buffer.status,
+				Header:                 buffer.header,
+				Body:                   io.NopCloser(bytes.NewReader(buffer.body.Bytes())),
+				Options:                options,
+			})
+			if err != nil {
+				writeProblem(w, r, pkg.StatusError{
+					Code:   http.StatusInternalServerError,
+					ErrMsg: fmt.Sprintf("response does not match the API specification: %s", err.Error()),
+				})
+				return
+			}
+
+			buffer.writeTo(w)
+		})
+	}, nil
+}
+
+// streamResponse reports whether operation answers with a body which is
+// not a JSON document.
+func streamResponse(operation *openapi3.Operation) bool {
+	response := operation.Responses.Get(http.StatusOK)
+	if response == nil || response.Value == nil || len(response.Value.Content) == 0 {
+		return false
+	}
+
+	return response.Value.Content.Get("application/json") == nil
+}
+
+func routeError(err error) error {
+	if errors.Is(err, routers.ErrMethodNotAllowed) {
+		return errMethodNotAllowed
+	}
+
+	return errRouteNotFound
+}
+
+// responseBuffer keeps a response until it is validated.
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{
+		header: http.Header{},
+		status: http.StatusOK,
+	}
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *responseBuffer) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+func (b *responseBuffer) writeTo(w http.ResponseWriter) {
+	for key, values := range b.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(b.status)
+
+	if _, err := w.Write(b.body.Bytes()); err != nil {
+		log.Println("failed to write response", err)
+	}
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+func (handler *HTTPHandler) CreateQuote(w http.ResponseWriter, r *http.Request) error {
+	request := model.QuoteRequest{}
+	if err := decode(r, &request, false); err != nil {
+		return err
+	}
+
+	quote, err := handler.WalletUC.Quote(r.Context(), request.FromCurrency, request.ToCurrency)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, quote)
+}
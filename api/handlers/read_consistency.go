@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/pkg"
+
+	"github.com/gorilla/mux"
+)
+
+// The headers which bound the staleness of the reads of a request.
+const (
+	MaxStalenessHeader = "X-Max-Staleness"
+	MinVersionHeader   = "X-Min-Version"
+)
+
+// ReadConsistency reads the staleness bound of a request into its context.
+// X-Max-Staleness is the number of seconds a replica may lag behind, 0
+// reads from the primary, and X-Min-Version is the wallet version or ETag
+// a read must see; maxStaleness is used without X-Max-Staleness.
+func ReadConsistency(maxStaleness time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			consistency, err := readConsistency(r, maxStaleness)
+			if err != nil {
+				writeProblem(w, r, statusError(err))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(pkg.WithReadConsistency(r.Context(), consistency)))
+		})
+	}
+}
+
+func readConsistency(r *http.Request, maxStaleness time.Duration) (pkg.ReadConsistency, error) {
+	consistency := pkg.ReadConsistency{MaxStaleness: maxStaleness}
+
+	if value := r.Header.Get(MaxStalenessHeader); value != "" {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 || seconds > math.MaxInt64/float64(time.Second) {
+			return consistency, errMaxStaleness
+		}
+		consistency.MaxStaleness = time.Duration(seconds * float64(time.Second))
+	}
+
+	if value := r.Header.Get(MinVersionHeader); value != "" {
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+
+		version, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || version < 1 {
+			return consistency, errMinVersion
+		}
+		consistency.MinVersion = version
+	}
+
+	return consistency, nil
+}
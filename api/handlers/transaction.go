@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+func (handler *HTTPHandler) ListTransactions(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	// validate request query params
+	query := r.URL.Query()
+	filter, err := parseLedgerFilter(query)
+	if err != nil {
+		return err
+	}
+
+	page, err := handler.WalletUC.ListTransactions(r.Context(), userID, walletID, filter, query.Get("cursor"))
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, page)
+}
+
+func parseLedgerFilter(query url.Values) (model.LedgerFilter, error) {
+	filter := model.LedgerFilter{}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, invalidField("limit", errInvalidLimit)
+		}
+		filter.Limit = limit
+	}
+
+	if v := query.Get("action"); v != "" {
+		switch model.ActionValue(v) {
+		case model.ActionDeposit, model.ActionWithdraw, model.ActionTransferIn, model.ActionTransferOut, model.ActionCapture:
+			filter.Action = model.ActionValue(v)
+		default:
+			return filter, invalidField("action", errInvalidFilter)
+		}
+	}
+
+	for key, dst := range map[string]**model.Money{
+		"min_amount": &filter.MinAmount,
+		"max_amount": &filter.MaxAmount,
+	} {
+		if v := query.Get(key); v != "" {
+			amount, err := model.NewMoney(v)
+			if err != nil || amount.IsNegative() {
+				return filter, invalidField(key, errInvalidFilter)
+			}
+			*dst = &amount
+		}
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && filter.MaxAmount.LessThan(*filter.MinAmount) {
+		return filter, invalidField("max_amount", errInvalidFilter)
+	}
+
+	for key, dst := range map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if v := query.Get(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, invalidField(key, errInvalidFilter)
+			}
+			*dst = &t
+		}
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, invalidField("to", errInvalidFilter)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/usecase/idempotency"
+)
+
+func (handler *HTTPHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) error {
+	request := model.TransferRequest{}
+	if err := decode(r, &request, false); err != nil {
+		return err
+	}
+
+	quoteID := ""
+	if request.QuoteID != nil {
+		quoteID = strconv.FormatInt(*request.QuoteID, 10)
+	}
+
+	fingerprint := idempotency.Fingerprint(
+		r.Method,
+		r.URL.Path,
+		strconv.FormatInt(request.FromWalletID, 10),
+		strconv.FormatInt(request.ToWalletID, 10),
+		request.Amount.String(),
+		string(request.Currency),
+		string(request.ToCurrency),
+		quoteID,
+	)
+
+	return handler.idempotent(w, r, fingerprint, func() (interface{}, error) {
+		return handler.WalletUC.Transfer(r.Context(), request)
+	})
+}
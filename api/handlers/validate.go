@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/sysdevguru/bluelabs/model"
+	"github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+	"github.com/sysdevguru/bluelabs/usecase/webhook"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MaxBodySize is the maximum size of a request body in bytes.
+const MaxBodySize = 1 << 16
+
+var validate = newValidator()
+
+// tagErrors are the errors of the validation tags which mean the same
+// on every field.
+var tagErrors = map[string]error{
+	"currency": errCurrency,
+	"scale":    wallet.ErrFundScale,
+}
+
+// fieldErrors are the errors of the request fields, by namespace.
+var fieldErrors = map[string]error{
+	"Transaction.action":                 errInvalidAction,
+	"Transaction.fund":                   errWalletFund,
+	"TransferRequest.from_wallet_id":     errWalletID,
+	"TransferRequest.to_wallet_id":       errWalletID,
+	"TransferRequest.amount":             errTransferAmount,
+	"TransferRequest.quote_id":           errQuoteID,
+	"HoldRequest.amount":                 errHoldAmount,
+	"HoldRequest.ttl_seconds":            wallet.ErrHoldTTL,
+	"CaptureRequest.amount":              errHoldAmount,
+	"CreateWalletRequest.type":           errWalletType,
+	"CloseWalletRequest.reason":          errCloseReason,
+	"CloseWalletRequest.sweep_wallet_id": wallet.ErrSweepWallet,
+	"WalletStatusRequest.status":         wallet.ErrWalletStatus,
+	"WalletStatusRequest.reason":         wallet.ErrStatusReason,
+	"WebhookRequest.url":                 webhook.ErrWebhookURL,
+	"WebhookRequest.events":              webhook.ErrWebhookEvents,
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// fields are reported by their JSON names
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	// amounts are compared as numbers by the builtin tags
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return field.Interface().(model.Money).Float64()
+	}, model.Money{})
+
+	mustRegister(v, "currency", func(fl validator.FieldLevel) bool {
+		return model.Currency(fl.Field().String()).Valid()
+	})
+
+	// scale=Currency checks the decimal places against the minor unit of
+	// the currency field, the amount is taken before its conversion
+	mustRegister(v, "scale", func(fl validator.FieldLevel) bool {
+		amount, ok := reflect.Indirect(fl.Parent().FieldByName(fl.StructFieldName())).Interface().(model.Money)
+		if !ok {
+			return false
+		}
+
+		currency := model.Currency(fl.Parent().FieldByName(fl.Param()).String())
+		return !currency.Valid() || amount.HasScale(currency.Exponent())
+	})
+
+	return v
+}
+
+func mustRegister(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		panic(err)
+	}
+}
+
+// decode reads the JSON body of r into request and validates it. Unknown
+// fields, trailing data and bodies over MaxBodySize are rejected, an empty
+// body is only accepted when optional.
+func decode(r *http.Request, request interface{}, optional bool) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		return invalidBody(err)
+	}
+	if len(body) > MaxBodySize {
+		return errBodyTooLarge
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(request)
+	if errors.Is(err, io.EOF) && !optional {
+		return invalidBody(io.ErrUnexpectedEOF)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return invalidBody(err)
+	}
+
+	if _, err = decoder.Token(); !errors.Is(err, io.EOF) {
+		return invalidBody(errTrailingData)
+	}
+
+	return validateRequest(request)
+}
+
+// validateRequest checks the validate tags of request and reports every
+// violated field, the first one is the error of the response.
+func validateRequest(request interface{}) error {
+	err := validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	validationErrs := validator.ValidationErrors{}
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	var statusErr pkg.StatusError
+	for i, fieldErr := range validationErrs {
+		fieldStatus := statusError(validationError(fieldErr))
+		if i == 0 {
+			statusErr = fieldStatus
+		}
+
+		statusErr.Fields = append(statusErr.Fields, pkg.FieldError{
+			Field:   strings.SplitN(fieldErr.Namespace(), ".", 2)[1],
+			Code:    fieldStatus.ErrorCode(),
+			Message: fieldStatus.ErrMsg,
+		})
+	}
+
+	return statusErr
+}
+
+func validationError(fieldErr validator.FieldError) error {
+	if err, ok := tagErrors[fieldErr.Tag()]; ok {
+		return err
+	}
+
+	// amounts are the only float fields
+	if fieldErr.Tag() == "max" && fieldErr.Kind() == reflect.Float64 {
+		return errAmountTooLarge
+	}
+
+	// the elements of a list are reported as the list
+	namespace := fieldErr.Namespace()
+	if i := strings.IndexByte(namespace, '['); i >= 0 {
+		namespace = namespace[:i]
+	}
+
+	if err, ok := fieldErrors[namespace]; ok {
+		return err
+	}
+
+	return errInvalidField
+}
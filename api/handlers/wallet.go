@@ -1,53 +1,71 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/sysdevguru/bluelabs/model"
-	"github.com/sysdevguru/bluelabs/pkg"
-
-	"github.com/gorilla/mux"
+	"github.com/sysdevguru/bluelabs/usecase/idempotency"
 )
 
 func (handler *HTTPHandler) GetWallet(w http.ResponseWriter, r *http.Request) error {
 	// validate request path params
-	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	userID, err := pathID(r, "userId", errUserID)
 	if err != nil {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrUserID,
-		}
+		return err
 	}
 
-	walletID, err := strconv.Atoi(mux.Vars(r)["walletId"])
+	walletID, err := pathID(r, "walletId", errWalletID)
 	if err != nil {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrWalletID,
-		}
+		return err
 	}
 
-	wallet, err := handler.WalletUC.GetWallet(r.Context(), int64(userID), int64(walletID))
+	wallet, err := handler.WalletUC.GetWallet(r.Context(), userID, walletID)
 	if err != nil {
 		return err
 	}
 
-	return renderJSON(w, wallet)
+	return renderResult(w, wallet)
+}
+
+func (handler *HTTPHandler) ListWallets(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	wallets, err := handler.WalletUC.ListWallets(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, wallets)
 }
 
 func (handler *HTTPHandler) CreateWallet(w http.ResponseWriter, r *http.Request) error {
 	// validate request path params
-	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	userID, err := pathID(r, "userId", errUserID)
 	if err != nil {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrUserID,
+		return err
+	}
+
+	request := model.CreateWalletRequest{}
+	if err = decode(r, &request, true); err != nil {
+		return err
+	}
+
+	if preferAsync(w, r) {
+		job, err := handler.WalletUC.CreateAsync(userID, request.Type, request.Currency)
+		if err != nil {
+			return err
 		}
+
+		return renderResult(w, job)
 	}
 
-	wallet, err := handler.WalletUC.Create(r.Context(), int64(userID))
+	wallet, err := handler.WalletUC.Create(r.Context(), userID, request.Type, request.Currency)
 	if err != nil {
 		return err
 	}
@@ -56,58 +74,111 @@ func (handler *HTTPHandler) CreateWallet(w http.ResponseWriter, r *http.Request)
 }
 
 func (handler *HTTPHandler) UpdateWallet(w http.ResponseWriter, r *http.Request) error {
-	var wallet *model.Wallet
-	var err error
-
 	// validate request path params
-	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	userID, err := pathID(r, "userId", errUserID)
 	if err != nil {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrUserID,
-		}
+		return err
 	}
 
-	walletID, err := strconv.Atoi(mux.Vars(r)["walletId"])
+	walletID, err := pathID(r, "walletId", errWalletID)
 	if err != nil {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrWalletID,
-		}
+		return err
 	}
 
 	transaction := model.Transaction{}
-	err = json.NewDecoder(r.Body).Decode(&transaction)
-	if err != nil {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: err.Error(),
-		}
+	if err = decode(r, &transaction, false); err != nil {
+		return err
 	}
 
-	// validate input data
-	if transaction.Fund < 0 {
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrWalletFund,
+	fingerprint := idempotency.Fingerprint(
+		r.Method,
+		r.URL.Path,
+		string(transaction.Action),
+		transaction.Fund.String(),
+		string(transaction.Currency),
+	)
+
+	async := preferAsync(w, r)
+	versions := ifMatch(r)
+	return handler.idempotent(w, r, fingerprint, func() (interface{}, error) {
+		if async && transaction.Action == model.ActionWithdraw {
+			return handler.WalletUC.WithdrawAsync(userID, walletID, transaction.Fund, transaction.Currency, versions)
 		}
-	}
-
-	switch transaction.Action {
-	case model.ActionDeposit:
-		if wallet, err = handler.WalletUC.Deposit(r.Context(), int64(userID), int64(walletID), transaction.Fund); err != nil {
-			return err
+		if async {
+			return handler.WalletUC.DepositAsync(userID, walletID, transaction.Fund, transaction.Currency, versions)
 		}
-	case model.ActionWithdraw:
-		if wallet, err = handler.WalletUC.Withdraw(r.Context(), int64(userID), int64(walletID), transaction.Fund); err != nil {
-			return err
+
+		if transaction.Action == model.ActionWithdraw {
+			return handler.WalletUC.Withdraw(r.Context(), userID, walletID, transaction.Fund, transaction.Currency, versions)
 		}
-	default:
-		return pkg.StatusError{
-			Code:   http.StatusBadRequest,
-			ErrMsg: pkg.ErrInvalidAction,
+
+		return handler.WalletUC.Deposit(r.Context(), userID, walletID, transaction.Fund, transaction.Currency, versions)
+	})
+}
+
+func (handler *HTTPHandler) CloseWallet(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	userID, err := pathID(r, "userId", errUserID)
+	if err != nil {
+		return err
+	}
+
+	walletID, err := pathID(r, "walletId", errWalletID)
+	if err != nil {
+		return err
+	}
+
+	request := model.CloseWalletRequest{}
+	if err = decode(r, &request, true); err != nil {
+		return err
+	}
+
+	sweepWalletID := ""
+	if request.SweepWalletID != nil {
+		sweepWalletID = strconv.FormatInt(*request.SweepWalletID, 10)
+	}
+
+	fingerprint := idempotency.Fingerprint(
+		r.Method,
+		r.URL.Path,
+		request.Reason,
+		sweepWalletID,
+	)
+
+	return handler.idempotent(w, r, fingerprint, func() (interface{}, error) {
+		return handler.WalletUC.Close(r.Context(), userID, walletID, request.Reason, request.SweepWalletID)
+	})
+}
+
+// walletETag is the strong entity tag of a wallet version.
+func walletETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatch returns the wallet versions of the If-Match header, nil when it
+// matches any version. Weak and unknown entity tags match no version.
+func ifMatch(r *http.Request) []int64 {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return nil
+	}
+
+	versions := []int64{}
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" {
+				return nil
+			}
+			if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+				continue
+			}
+
+			if version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil {
+				versions = append(versions, version)
+			}
 		}
 	}
 
-	return renderJSON(w, wallet)
+	return versions
 }
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// deliveryStatusParam filters the listed deliveries by status.
+const deliveryStatusParam = "status"
+
+func (handler *HTTPHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) error {
+	request := model.WebhookRequest{}
+	if err := decode(r, &request, false); err != nil {
+		return err
+	}
+
+	webhook, err := handler.WebhookUC.Create(r.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, webhook)
+}
+
+func (handler *HTTPHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) error {
+	webhooks, err := handler.WebhookUC.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, webhooks)
+}
+
+func (handler *HTTPHandler) GetWebhook(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	webhookID, err := pathID(r, "webhookId", errWebhookID)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := handler.WebhookUC.Get(r.Context(), webhookID)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, webhook)
+}
+
+func (handler *HTTPHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	webhookID, err := pathID(r, "webhookId", errWebhookID)
+	if err != nil {
+		return err
+	}
+
+	if err = handler.WebhookUC.Delete(r.Context(), webhookID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (handler *HTTPHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	webhookID, err := pathID(r, "webhookId", errWebhookID)
+	if err != nil {
+		return err
+	}
+
+	status := model.DeliveryStatus(r.URL.Query().Get(deliveryStatusParam))
+	deliveries, err := handler.WebhookUC.ListDeliveries(r.Context(), webhookID, status)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, deliveries)
+}
+
+func (handler *HTTPHandler) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) error {
+	// validate request path params
+	webhookID, err := pathID(r, "webhookId", errWebhookID)
+	if err != nil {
+		return err
+	}
+
+	deliveryID, err := pathID(r, "deliveryId", errDeliveryID)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := handler.WebhookUC.Replay(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	return renderJSON(w, delivery)
+}
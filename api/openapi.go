@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// openAPISpec is the OpenAPI document of the routes of NewRouter.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// LoadOpenAPI parses and validates the OpenAPI document of the API.
+func LoadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,131 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	. "github.com/sysdevguru/bluelabs/api"
+	"github.com/sysdevguru/bluelabs/api/handlers"
+	"github.com/sysdevguru/bluelabs/pkg"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("OpenAPI", func() {
+	var doc *openapi3.T
+
+	BeforeEach(func() {
+		var err error
+		doc, err = LoadOpenAPI()
+		assert.NoError(GinkgoT(), err)
+	})
+
+	It("documents every route of the router", func() {
+		routes := []string{}
+		err := NewRouter(&Service{}).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+			path, err := route.GetPathTemplate()
+			if err != nil {
+				return err
+			}
+			methods, err := route.GetMethods()
+			if err != nil {
+				return err
+			}
+			for _, method := range methods {
+				routes = append(routes, method+" "+path)
+			}
+			return nil
+		})
+		assert.NoError(GinkgoT(), err)
+
+		operations := []string{}
+		for path, item := range doc.Paths {
+			for method := range item.Operations() {
+				operations = append(operations, method+" "+path)
+			}
+		}
+
+		sort.Strings(routes)
+		sort.Strings(operations)
+		assert.Equal(GinkgoT(), routes, operations)
+	})
+
+	It("documents every error code", func() {
+		codes := []string{}
+		for _, code := range doc.Components.Schemas["ErrorCode"].Value.Enum {
+			codes = append(codes, code.(string))
+		}
+
+		sort.Strings(codes)
+		assert.Equal(GinkgoT(), handlers.ErrorCodes(), codes)
+	})
+
+	It("serves the specification", func() {
+		resp := httptest.NewRecorder()
+		NewRouter(&Service{}).ServeHTTP(resp, httptest.NewRequest("GET", "/openapi.json", nil))
+
+		assert.Equal(GinkgoT(), 200, resp.Code)
+		assert.Equal(GinkgoT(), "application/json", resp.Header().Get("Content-Type"))
+
+		served := openapi3.T{}
+		assert.NoError(GinkgoT(), json.NewDecoder(resp.Body).Decode(&served))
+		assert.Equal(GinkgoT(), doc.Info.Title, served.Info.Title)
+	})
+
+	Context("Validation", func() {
+		var (
+			router *mux.Router
+			body   string
+		)
+
+		BeforeEach(func() {
+			validate, err := handlers.ValidateOpenAPI(doc)
+			assert.NoError(GinkgoT(), err)
+
+			body = `[]`
+			router = mux.NewRouter()
+			router.Use(validate)
+			router.HandleFunc("/users/{userId}/wallets", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			}).Methods(http.MethodGet, http.MethodPost)
+		})
+
+		It("passes valid traffic", func() {
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, httptest.NewRequest("GET", "/users/1/wallets", nil))
+
+			assert.Equal(GinkgoT(), 200, resp.Code)
+			assert.Equal(GinkgoT(), "[]", resp.Body.String())
+		})
+
+		It("rejects invalid requests", func() {
+			req := httptest.NewRequest("POST", "/users/1/wallets", strings.NewReader(`{"currency": "euro"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			problem := pkg.Problem{}
+			assert.Equal(GinkgoT(), 400, resp.Code)
+			assert.NoError(GinkgoT(), json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(GinkgoT(), "INVALID_REQUEST", problem.Code)
+		})
+
+		It("fails invalid responses", func() {
+			body = `[{"id": "one"}]`
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, httptest.NewRequest("GET", "/users/1/wallets", nil))
+
+			problem := pkg.Problem{}
+			assert.Equal(GinkgoT(), 500, resp.Code)
+			assert.NoError(GinkgoT(), json.NewDecoder(resp.Body).Decode(&problem))
+			assert.Equal(GinkgoT(), "INTERNAL_SERVER_ERROR", problem.Code)
+		})
+	})
+})
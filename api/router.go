@@ -9,12 +9,45 @@ import (
 )
 
 func NewRouter(service *Service) *mux.Router {
-	handler := handlers.HTTPHandler{WalletUC: service.walletUC}
+	handler := handlers.HTTPHandler{
+		WalletUC:      service.walletUC,
+		IdempotencyUC: service.idempotencyUC,
+		WebhookUC:     service.webhookUC,
+		Events:        service.events,
+		EventsCfg:     service.cfg.Events,
+	}
 
 	r := mux.NewRouter()
+	r.Use(handlers.RequestID)
+	r.Use(handlers.ReadConsistency(service.cfg.Database.ReplicaMaxStaleness))
+	if service.openAPIValidator != nil {
+		r.Use(service.openAPIValidator)
+	}
+	r.NotFoundHandler = handlers.RequestID(handlers.HTTPHandler{Handle: handlers.NotFound})
+	r.MethodNotAllowedHandler = handlers.RequestID(handlers.HTTPHandler{Handle: handlers.MethodNotAllowed})
 	r.Handle("/users/{userId}/wallets/{walletId}", handlers.HTTPHandler{Handle: handler.GetWallet}).Methods(http.MethodGet)
+	r.Handle("/users/{userId}/wallets", handlers.HTTPHandler{Handle: handler.ListWallets}).Methods(http.MethodGet)
 	r.Handle("/users/{userId}/wallets", handlers.HTTPHandler{Handle: handler.CreateWallet}).Methods(http.MethodPost)
 	r.Handle("/users/{userId}/wallets/{walletId}", handlers.HTTPHandler{Handle: handler.UpdateWallet}).Methods(http.MethodPut)
+	r.Handle("/users/{userId}/wallets/{walletId}", handlers.HTTPHandler{Handle: handler.CloseWallet}).Methods(http.MethodDelete)
+	r.Handle("/users/{userId}/wallets/{walletId}/transactions", handlers.HTTPHandler{Handle: handler.ListTransactions}).Methods(http.MethodGet)
+	r.Handle("/users/{userId}/wallets/{walletId}/events", handlers.HTTPHandler{Handle: handler.StreamEvents}).Methods(http.MethodGet)
+	r.Handle("/users/{userId}/wallets/{walletId}/holds", handlers.HTTPHandler{Handle: handler.CreateHold}).Methods(http.MethodPost)
+	r.Handle("/users/{userId}/wallets/{walletId}/holds/{holdId}", handlers.HTTPHandler{Handle: handler.GetHold}).Methods(http.MethodGet)
+	r.Handle("/users/{userId}/wallets/{walletId}/holds/{holdId}/capture", handlers.HTTPHandler{Handle: handler.CaptureHold}).Methods(http.MethodPost)
+	r.Handle("/users/{userId}/wallets/{walletId}/holds/{holdId}/release", handlers.HTTPHandler{Handle: handler.ReleaseHold}).Methods(http.MethodPost)
+	r.Handle("/transfers", handlers.HTTPHandler{Handle: handler.CreateTransfer}).Methods(http.MethodPost)
+	r.Handle("/quotes", handlers.HTTPHandler{Handle: handler.CreateQuote}).Methods(http.MethodPost)
+	r.Handle("/admin/wallets/{walletId}/status", handlers.HTTPHandler{Handle: handler.SetWalletStatus}).Methods(http.MethodPut)
+	r.Handle("/admin/wallets/{walletId}/status", handlers.HTTPHandler{Handle: handler.ListWalletStatusChanges}).Methods(http.MethodGet)
+	r.Handle("/admin/webhooks", handlers.HTTPHandler{Handle: handler.CreateWebhook}).Methods(http.MethodPost)
+	r.Handle("/admin/webhooks", handlers.HTTPHandler{Handle: handler.ListWebhooks}).Methods(http.MethodGet)
+	r.Handle("/admin/webhooks/{webhookId}", handlers.HTTPHandler{Handle: handler.GetWebhook}).Methods(http.MethodGet)
+	r.Handle("/admin/webhooks/{webhookId}", handlers.HTTPHandler{Handle: handler.DeleteWebhook}).Methods(http.MethodDelete)
+	r.Handle("/admin/webhooks/{webhookId}/deliveries", handlers.HTTPHandler{Handle: handler.ListWebhookDeliveries}).Methods(http.MethodGet)
+	r.Handle("/admin/webhooks/{webhookId}/deliveries/{deliveryId}/replay", handlers.HTTPHandler{Handle: handler.ReplayWebhookDelivery}).Methods(http.MethodPost)
+	r.Handle("/jobs/{jobId}", handlers.HTTPHandler{Handle: handler.GetJob}).Methods(http.MethodGet)
+	r.Handle("/openapi.json", handlers.HTTPHandler{Handle: handlers.OpenAPI(openAPISpec)}).Methods(http.MethodGet)
 
 	return r
 }
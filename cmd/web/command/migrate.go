@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/migrations"
+	"github.com/sysdevguru/bluelabs/pkg"
+
+	"github.com/pkg/errors"
+)
+
+// migrateUsage describes the arguments of the migrate command.
+const migrateUsage = "usage: web migrate up|down|status|to <version>|baseline <version>"
+
+// Migrate changes the schema of the database as args ask: up applies the
+// pending migrations, down reverts the last one, status lists them and to
+// moves the schema to a version, 0 reverts every migration. baseline records
+// the migrations up to a version as applied, for a schema set up by the SQL
+// scripts before the migrations.
+func Migrate(ctx context.Context, cfg pkg.Config, args []string) error {
+	run, err := migration(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	db, err := pkg.NewGormWithPostgres(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect database")
+	}
+	defer pkg.CloseDatabaseConnection(db)
+
+	migrator, err := pkg.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return errors.Wrap(err, "failed to load migrations")
+	}
+
+	return run(migrator)
+}
+
+// migration is the run of the migrator args ask for.
+func migration(ctx context.Context, args []string) (func(*pkg.Migrator) error, error) {
+	switch {
+	case len(args) == 1 && args[0] == "up":
+		return func(m *pkg.Migrator) error { return m.Up(ctx) }, nil
+	case len(args) == 1 && args[0] == "down":
+		return func(m *pkg.Migrator) error { return m.Down(ctx) }, nil
+	case len(args) == 1 && args[0] == "status":
+		return func(m *pkg.Migrator) error { return printStatus(ctx, m) }, nil
+	case len(args) == 2 && (args[0] == "to" || args[0] == "baseline"):
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid version %q", args[1])
+		}
+
+		if args[0] == "baseline" {
+			return func(m *pkg.Migrator) error { return m.Baseline(ctx, version) }, nil
+		}
+		return func(m *pkg.Migrator) error { return m.To(ctx, version) }, nil
+	}
+
+	return nil, errors.New(migrateUsage)
+}
+
+// printStatus writes a line per migration with when it was applied.
+func printStatus(ctx context.Context, migrator *pkg.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\t")
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Format(time.RFC3339)
+		}
+
+		note := ""
+		if status.Modified {
+			note = "modified"
+		}
+		if status.Unknown {
+			note = "unknown"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, applied, note)
+	}
+
+	return w.Flush()
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/sysdevguru/bluelabs/api"
 	"github.com/sysdevguru/bluelabs/pkg"
+
+	"google.golang.org/grpc"
 )
 
 type StopFn func(context.Context) error
@@ -33,13 +37,40 @@ func Start(ctx context.Context, cfg pkg.Config) StopFn {
 		}
 	}()
 
+	grpcServer := api.NewGRPCServer(service)
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
+	if err != nil {
+		log.Fatalf("could not listen on the gRPC port %s\n", err.Error())
+	}
+
+	go func() {
+		log.Println("gRPC server initialized...")
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatalf("grpc.Serve failed %v\n", err)
+		}
+	}()
+
+	jobCtx, cancelJobs := context.WithCancel(ctx)
+	go every(jobCtx, cfg.Idempotency.PurgeInterval, "purge idempotency keys", service.PurgeIdempotencyKeys)
+	go every(jobCtx, cfg.Holds.SweepInterval, "release expired holds", service.ReleaseExpiredHolds)
+	go every(jobCtx, cfg.Webhooks.PollInterval, "deliver webhooks", service.DeliverWebhooks)
+	go every(jobCtx, cfg.Outbox.PollInterval, "relay outbox", service.RelayOutbox)
+
 	return func(stopCtx context.Context) error {
+		cancelJobs()
+
 		err := server.Shutdown(stopCtx)
 		if err != nil {
 			log.Println("server failed to shutdown gracefully", err)
 			return err
 		}
 
+		err = stopGRPC(stopCtx, grpcServer)
+		if err != nil {
+			log.Println("gRPC server failed to shutdown gracefully", err)
+			return err
+		}
+
 		err = service.Shutdown()
 		if err != nil {
 			log.Println("service failed to shutdown gracefully", err)
@@ -50,3 +81,38 @@ func Start(ctx context.Context, cfg pkg.Config) StopFn {
 		return nil
 	}
 }
+
+// stopGRPC waits for the running calls of server until ctx is done,
+// then cancels them.
+func stopGRPC(ctx context.Context, server *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// every runs job at each interval until ctx is done.
+func every(ctx context.Context, interval time.Duration, name string, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Printf("%s failed %v\n", name, err)
+			}
+		}
+	}
+}
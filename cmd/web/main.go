@@ -18,6 +18,14 @@ func main() {
 	if err != nil {
 		log.Fatalf("could not load configuration %s\n", err.Error())
 	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := command.Migrate(ctx, cfg, os.Args[2:]); err != nil {
+			log.Fatalf("could not migrate %s\n", err.Error())
+		}
+		os.Exit(0)
+	}
+
 	stopFn := command.Start(ctx, cfg)
 
 	sig := make(chan os.Signal, 1)
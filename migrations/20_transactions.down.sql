@@ -0,0 +1,3 @@
+DROP TABLE transactions;
+
+DROP FUNCTION transactions_immutable();
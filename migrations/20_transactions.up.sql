@@ -0,0 +1,27 @@
+CREATE TABLE transactions (
+    id BIGSERIAL,
+    wallet_id bigint NOT NULL,
+    action varchar(16) NOT NULL,
+    amount float NOT NULL,
+    balance_after float NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transaction_id_pkey PRIMARY KEY (id);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transaction_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets (id);
+
+CREATE INDEX transaction_wallet_id_idx ON transactions (wallet_id, id);
+
+-- ledger entries are append only
+CREATE FUNCTION transactions_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'transactions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_immutable
+    BEFORE UPDATE OR DELETE ON transactions
+    FOR EACH ROW EXECUTE PROCEDURE transactions_immutable();
@@ -0,0 +1,8 @@
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE float USING balance::float,
+    ALTER COLUMN balance DROP DEFAULT,
+    ALTER COLUMN balance DROP NOT NULL;
+
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE float USING amount::float,
+    ALTER COLUMN balance_after TYPE float USING balance_after::float;
@@ -0,0 +1,11 @@
+-- Amounts used to be stored as float which drifts on repeated additions.
+-- Casting float to numeric keeps 15 significant digits, which drops the
+-- binary drift without losing any meaningful digit of the balances.
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE numeric(20, 4) USING round(coalesce(balance, 0)::numeric, 4),
+    ALTER COLUMN balance SET DEFAULT 0,
+    ALTER COLUMN balance SET NOT NULL;
+
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE numeric(20, 4) USING round(amount::numeric, 4),
+    ALTER COLUMN balance_after TYPE numeric(20, 4) USING round(balance_after::numeric, 4);
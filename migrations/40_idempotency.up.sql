@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key varchar(255) NOT NULL,
+    fingerprint char(64) NOT NULL,
+    status_code int NOT NULL DEFAULT 0,
+    response bytea,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE idempotency_keys
+    ADD CONSTRAINT idempotency_key_pkey PRIMARY KEY (idempotency_key);
+
+CREATE INDEX idempotency_key_created_at_idx ON idempotency_keys (created_at);
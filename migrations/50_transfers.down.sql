@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    DROP COLUMN transfer_id;
+
+DROP TABLE transfers;
@@ -0,0 +1,26 @@
+CREATE TABLE transfers (
+    id BIGSERIAL,
+    from_wallet_id bigint NOT NULL,
+    to_wallet_id bigint NOT NULL,
+    amount numeric(20, 4) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_id_pkey PRIMARY KEY (id);
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_from_wallet_id_fkey FOREIGN KEY (from_wallet_id) REFERENCES wallets (id);
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_to_wallet_id_fkey FOREIGN KEY (to_wallet_id) REFERENCES wallets (id);
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_distinct_wallets CHECK (from_wallet_id <> to_wallet_id);
+
+-- both legs of a transfer point to it
+ALTER TABLE transactions
+    ADD COLUMN transfer_id bigint;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transaction_transfer_id_fkey FOREIGN KEY (transfer_id) REFERENCES transfers (id);
@@ -0,0 +1,8 @@
+ALTER TABLE transactions
+    DROP COLUMN hold_id;
+
+DROP TABLE holds;
+
+ALTER TABLE wallets
+    DROP CONSTRAINT wallet_held_within_balance,
+    DROP COLUMN held;
@@ -0,0 +1,30 @@
+ALTER TABLE wallets
+    ADD COLUMN held numeric(20, 4) NOT NULL DEFAULT 0;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT wallet_held_within_balance CHECK (held >= 0 AND held <= balance);
+
+CREATE TABLE holds (
+    id BIGSERIAL,
+    wallet_id bigint NOT NULL,
+    amount numeric(20, 4) NOT NULL,
+    captured numeric(20, 4) NOT NULL DEFAULT 0,
+    status varchar(16) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE holds
+    ADD CONSTRAINT hold_id_pkey PRIMARY KEY (id);
+
+ALTER TABLE holds
+    ADD CONSTRAINT hold_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets (id);
+
+CREATE INDEX hold_active_expires_at_idx ON holds (expires_at) WHERE status = 'active';
+
+ALTER TABLE transactions
+    ADD COLUMN hold_id bigint;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transaction_hold_id_fkey FOREIGN KEY (hold_id) REFERENCES holds (id);
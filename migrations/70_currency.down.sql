@@ -0,0 +1,12 @@
+ALTER TABLE transfers
+    DROP COLUMN currency;
+
+-- fails while a user has wallets in several currencies
+ALTER TABLE wallets
+    DROP CONSTRAINT user_id_currency_unique;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT user_id_unique UNIQUE (user_id);
+
+ALTER TABLE wallets
+    DROP COLUMN currency;
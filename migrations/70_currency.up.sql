@@ -0,0 +1,18 @@
+-- wallets created before currencies were supported hold euros
+ALTER TABLE wallets
+    ADD COLUMN currency char(3) NOT NULL DEFAULT 'EUR';
+
+ALTER TABLE wallets
+    ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE wallets
+    DROP CONSTRAINT user_id_unique;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT user_id_currency_unique UNIQUE (user_id, currency);
+
+ALTER TABLE transfers
+    ADD COLUMN currency char(3) NOT NULL DEFAULT 'EUR';
+
+ALTER TABLE transfers
+    ALTER COLUMN currency DROP DEFAULT;
@@ -0,0 +1,12 @@
+ALTER TABLE transactions
+    DROP COLUMN rate;
+
+ALTER TABLE transfers
+    DROP CONSTRAINT transfer_quote_id_unique,
+    DROP CONSTRAINT transfer_quote_id_fkey,
+    DROP COLUMN to_amount,
+    DROP COLUMN to_currency,
+    DROP COLUMN rate,
+    DROP COLUMN quote_id;
+
+DROP TABLE quotes;
@@ -0,0 +1,40 @@
+CREATE TABLE quotes (
+    id BIGSERIAL,
+    from_currency char(3) NOT NULL,
+    to_currency char(3) NOT NULL,
+    rate numeric(30, 10) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE quotes
+    ADD CONSTRAINT quote_id_pkey PRIMARY KEY (id);
+
+ALTER TABLE quotes
+    ADD CONSTRAINT quote_rate_positive CHECK (rate > 0);
+
+-- transfers made before conversions were supported are in one currency
+ALTER TABLE transfers
+    ADD COLUMN to_amount numeric(20, 4),
+    ADD COLUMN to_currency char(3),
+    ADD COLUMN rate numeric(30, 10) NOT NULL DEFAULT 1,
+    ADD COLUMN quote_id bigint;
+
+UPDATE transfers SET to_amount = amount, to_currency = currency;
+
+ALTER TABLE transfers
+    ALTER COLUMN to_amount SET NOT NULL,
+    ALTER COLUMN to_currency SET NOT NULL,
+    ALTER COLUMN rate DROP DEFAULT;
+
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_quote_id_fkey FOREIGN KEY (quote_id) REFERENCES quotes (id);
+
+-- a quote locks its rate for a single transfer
+ALTER TABLE transfers
+    ADD CONSTRAINT transfer_quote_id_unique UNIQUE (quote_id);
+
+-- the rate applied to both legs of a conversion
+ALTER TABLE transactions
+    ADD COLUMN rate numeric(30, 10);
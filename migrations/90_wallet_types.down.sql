@@ -0,0 +1,10 @@
+-- fails while a user has wallets of several types in a currency
+ALTER TABLE wallets
+    DROP CONSTRAINT user_id_type_currency_unique;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT user_id_currency_unique UNIQUE (user_id, currency);
+
+ALTER TABLE wallets
+    DROP CONSTRAINT wallet_type_check,
+    DROP COLUMN type;
@@ -0,0 +1,15 @@
+-- wallets created before types were supported hold cash
+ALTER TABLE wallets
+    ADD COLUMN type varchar(20) NOT NULL DEFAULT 'cash';
+
+ALTER TABLE wallets
+    ALTER COLUMN type DROP DEFAULT;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT wallet_type_check CHECK (type IN ('cash', 'bonus', 'locked_winnings'));
+
+ALTER TABLE wallets
+    DROP CONSTRAINT user_id_currency_unique;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT user_id_type_currency_unique UNIQUE (user_id, type, currency);
@@ -0,0 +1,9 @@
+-- fails while a closed wallet has been replaced by a new one
+DROP INDEX user_id_type_currency_unique;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT user_id_type_currency_unique UNIQUE (user_id, type, currency);
+
+ALTER TABLE wallets
+    DROP COLUMN closed_at,
+    DROP COLUMN close_reason;
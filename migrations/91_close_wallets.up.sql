@@ -0,0 +1,12 @@
+-- closed wallets keep their row and ledger
+ALTER TABLE wallets
+    ADD COLUMN closed_at timestamptz,
+    ADD COLUMN close_reason varchar(255);
+
+-- a closed wallet does not prevent opening a new one of the same type and currency
+ALTER TABLE wallets
+    DROP CONSTRAINT user_id_type_currency_unique;
+
+CREATE UNIQUE INDEX user_id_type_currency_unique
+    ON wallets (user_id, type, currency)
+    WHERE closed_at IS NULL;
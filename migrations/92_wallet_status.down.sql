@@ -0,0 +1,6 @@
+DROP TABLE wallet_status_changes;
+
+ALTER TABLE wallets
+    DROP CONSTRAINT wallet_status_check,
+    DROP COLUMN status,
+    DROP COLUMN status_reason;
@@ -0,0 +1,29 @@
+ALTER TABLE wallets
+    ADD COLUMN status varchar(20) NOT NULL DEFAULT 'active',
+    ADD COLUMN status_reason varchar(255);
+
+UPDATE wallets SET status = 'closed', status_reason = close_reason WHERE closed_at IS NOT NULL;
+
+ALTER TABLE wallets
+    ALTER COLUMN status DROP DEFAULT;
+
+ALTER TABLE wallets
+    ADD CONSTRAINT wallet_status_check CHECK (status IN ('active', 'withdraw_blocked', 'frozen', 'closed'));
+
+-- audit trail of the status changes
+CREATE TABLE wallet_status_changes (
+    id BIGSERIAL,
+    wallet_id bigint NOT NULL,
+    from_status varchar(20) NOT NULL,
+    to_status varchar(20) NOT NULL,
+    reason varchar(255) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE wallet_status_changes
+    ADD CONSTRAINT wallet_status_change_id_pkey PRIMARY KEY (id);
+
+ALTER TABLE wallet_status_changes
+    ADD CONSTRAINT wallet_status_change_wallet_id_fkey FOREIGN KEY (wallet_id) REFERENCES wallets (id);
+
+CREATE INDEX wallet_status_changes_wallet_id_idx ON wallet_status_changes (wallet_id);
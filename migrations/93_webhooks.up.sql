@@ -0,0 +1,40 @@
+CREATE TABLE webhooks (
+    id BIGSERIAL,
+    url varchar(2048) NOT NULL,
+    events text NOT NULL,
+    secret char(64) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE webhooks
+    ADD CONSTRAINT webhook_id_pkey PRIMARY KEY (id);
+
+-- queue of the events to deliver to each webhook
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL,
+    webhook_id bigint NOT NULL,
+    event_id char(32) NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload bytea NOT NULL,
+    status varchar(20) NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    last_status_code int NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    delivered_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_delivery_id_pkey PRIMARY KEY (id);
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_delivery_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE;
+
+ALTER TABLE webhook_deliveries
+    ADD CONSTRAINT webhook_delivery_status_check CHECK (status IN ('pending', 'delivered', 'dead'));
+
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);
+
+-- only the pending deliveries are polled
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,11 @@
+-- domain events saved with the changes they describe, deleted once published
+CREATE TABLE outbox (
+    id BIGSERIAL,
+    wallet_id bigint NOT NULL,
+    type varchar(50) NOT NULL,
+    payload bytea NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER TABLE outbox
+    ADD CONSTRAINT outbox_id_pkey PRIMARY KEY (id);
@@ -0,0 +1,3 @@
+-- incremented by every change of a wallet, for optimistic locking and ETags
+ALTER TABLE wallets
+    ADD COLUMN version bigint NOT NULL DEFAULT 1;
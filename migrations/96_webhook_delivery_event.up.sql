@@ -0,0 +1,2 @@
+-- the outbox relay may publish an event again, its deliveries are created once
+CREATE UNIQUE INDEX webhook_deliveries_event_idx ON webhook_deliveries (webhook_id, event_id);
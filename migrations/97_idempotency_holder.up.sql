@@ -0,0 +1,4 @@
+-- the token of the request holding a reservation, a stale reservation is
+-- taken over by a new request and the original one must not change it
+ALTER TABLE idempotency_keys
+    ADD COLUMN holder char(32) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+// Package migrations embeds the versioned changes of the database schema.
+// A migration is a pair of <version>_<name>.up.sql and .down.sql files,
+// they are applied in version order and reverted in the reverse order.
+package migrations
+
+import "embed"
+
+// FS holds the migration files.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,39 @@
+package model
+
+// Currency is an ISO-4217 currency code.
+type Currency string
+
+// currencyExponents are the supported currencies with the number of
+// decimal places of their minor unit.
+var currencyExponents = map[Currency]int32{
+	"AUD": 2,
+	"BHD": 3,
+	"CAD": 2,
+	"CHF": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"ISK": 0,
+	"JPY": 0,
+	"KWD": 3,
+	"NOK": 2,
+	"PLN": 2,
+	"SEK": 2,
+	"USD": 2,
+}
+
+// Valid reports whether c is a supported ISO-4217 code.
+func (c Currency) Valid() bool {
+	_, ok := currencyExponents[c]
+	return ok
+}
+
+// Exponent is the number of decimal places of the minor unit of c.
+func (c Currency) Exponent() int32 {
+	return currencyExponents[c]
+}
+
+// Round rounds m half to even to the minor unit of c.
+func (c Currency) Round(m Money) Money {
+	return m.RoundBank(c.Exponent())
+}
@@ -0,0 +1,24 @@
+package model_test
+
+import (
+	. "github.com/sysdevguru/bluelabs/model"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("Currency", func() {
+	It("validates ISO-4217 codes", func() {
+		assert.True(GinkgoT(), Currency("EUR").Valid())
+		assert.True(GinkgoT(), Currency("SEK").Valid())
+		assert.False(GinkgoT(), Currency("eur").Valid())
+		assert.False(GinkgoT(), Currency("XXX").Valid())
+	})
+
+	It("rounds to the minor unit", func() {
+		assert.Equal(GinkgoT(), "10.12", Currency("EUR").Round(RequireMoney("10.125")).String())
+		assert.Equal(GinkgoT(), "10.14", Currency("GBP").Round(RequireMoney("10.135")).String())
+		assert.Equal(GinkgoT(), "10", Currency("JPY").Round(RequireMoney("10.5")).String())
+		assert.Equal(GinkgoT(), "10.125", Currency("BHD").Round(RequireMoney("10.125")).String())
+	})
+})
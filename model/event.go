@@ -0,0 +1,20 @@
+package model
+
+// BalanceEvent is a change of the balance of a wallet, its id is the id of
+// the ledger entry which caused it so the events of a wallet are ordered.
+type BalanceEvent struct {
+	ID       int64       `json:"id"`
+	WalletID int64       `json:"wallet_id"`
+	Balance  Money       `json:"balance"`
+	Entry    LedgerEntry `json:"entry"`
+}
+
+// NewBalanceEvent is the balance event of a ledger entry.
+func NewBalanceEvent(entry LedgerEntry) BalanceEvent {
+	return BalanceEvent{
+		ID:       entry.ID,
+		WalletID: entry.WalletID,
+		Balance:  entry.BalanceAfter,
+		Entry:    entry,
+	}
+}
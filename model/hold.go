@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type HoldStatus string
+
+const (
+	HoldActive   HoldStatus = "active"
+	HoldCaptured HoldStatus = "captured"
+	HoldReleased HoldStatus = "released"
+	HoldExpired  HoldStatus = "expired"
+)
+
+// Hold reserves funds of a wallet until it is captured, released or expires.
+type Hold struct {
+	ID        int64      `json:"id"`
+	WalletID  int64      `json:"wallet_id"`
+	Amount    Money      `json:"amount"`
+	Captured  Money      `json:"captured"`
+	Status    HoldStatus `json:"status"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	Wallet *Wallet `json:"wallet,omitempty" gorm:"-"`
+}
+
+// HoldRequest is the payload to place a hold.
+type HoldRequest struct {
+	Amount     Money `json:"amount" validate:"required,gt=0,max=1000000000000"`
+	TTLSeconds int   `json:"ttl_seconds" validate:"gte=0,max=86400"`
+}
+
+// CaptureRequest is the payload to capture a hold, the whole hold
+// is captured when Amount is omitted.
+type CaptureRequest struct {
+	Amount *Money `json:"amount,omitempty" validate:"omitempty,gt=0,max=1000000000000"`
+}
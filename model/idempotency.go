@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// IdempotencyRecord stores the outcome of a request sent with an Idempotency-Key.
+// StatusCode is zero while the request is still in progress.
+type IdempotencyRecord struct {
+	Key         string `gorm:"column:idempotency_key;primaryKey"`
+	Fingerprint string
+	// Holder is the token of the request which reserved the key, only it
+	// completes or releases the reservation.
+	Holder     string
+	StatusCode int
+	Response   []byte
+	CreatedAt  time.Time
+}
+
+// Completed reports whether the response of the request is stored.
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// TableName maps IdempotencyRecord to the idempotency_keys table.
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a request run in the background. Result is set once it
+// succeeded and Err once it failed.
+type Job struct {
+	ID         string      `json:"id"`
+	Task       string      `json:"task"`
+	Status     JobStatus   `json:"status"`
+	Result     interface{} `json:"result,omitempty"`
+	Err        error       `json:"-"`
+	CreatedAt  time.Time   `json:"created_at"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job has its outcome.
+func (j *Job) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
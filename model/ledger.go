@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// LedgerEntry is an immutable record of a single balance change.
+type LedgerEntry struct {
+	ID           int64       `json:"id"`
+	WalletID     int64       `json:"wallet_id"`
+	Action       ActionValue `json:"action"`
+	Amount       Money       `json:"amount"`
+	BalanceAfter Money       `json:"balance_after"`
+	TransferID   *int64      `json:"transfer_id,omitempty"`
+	HoldID       *int64      `json:"hold_id,omitempty"`
+	Rate         *Rate       `json:"rate,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// TableName maps LedgerEntry to the transactions table.
+func (LedgerEntry) TableName() string {
+	return "transactions"
+}
+
+// LedgerFilter narrows down the ledger entries of a wallet.
+type LedgerFilter struct {
+	Action    ActionValue
+	MinAmount *Money
+	MaxAmount *Money
+	From      *time.Time
+	To        *time.Time
+
+	// BeforeID only keeps the entries older than the given entry.
+	BeforeID int64
+	// AfterID only keeps the entries newer than the given entry,
+	// they are returned oldest first.
+	AfterID int64
+	Limit   int
+}
+
+// LedgerPage is a page of ledger entries ordered newest first.
+type LedgerPage struct {
+	Entries    []LedgerEntry `json:"entries"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
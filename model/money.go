@@ -0,0 +1,125 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+var errInvalidMoney = errors.New("invalid money amount")
+
+// Money is an exact decimal amount of money.
+// It is encoded as a JSON string and stored in a numeric column.
+type Money struct {
+	d decimal.Decimal
+}
+
+// NewMoney parses an amount such as "12.34".
+func NewMoney(value string) (Money, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Money{}, errInvalidMoney
+	}
+
+	return Money{d}, nil
+}
+
+// RequireMoney parses an amount and panics when it is invalid.
+func RequireMoney(value string) Money {
+	m, err := NewMoney(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{m.d.Add(o.d)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{m.d.Sub(o.d)}
+}
+
+func (m Money) Cmp(o Money) int {
+	return m.d.Cmp(o.d)
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.d.Equal(o.d)
+}
+
+func (m Money) LessThan(o Money) bool {
+	return m.d.LessThan(o.d)
+}
+
+func (m Money) IsZero() bool {
+	return m.d.IsZero()
+}
+
+func (m Money) IsNegative() bool {
+	return m.d.IsNegative()
+}
+
+// RoundBank rounds m half to even to places decimal places.
+func (m Money) RoundBank(places int32) Money {
+	return Money{m.d.RoundBank(places)}
+}
+
+// HasScale reports whether the amount has at most places decimal places.
+func (m Money) HasScale(places int32) bool {
+	return m.d.Equal(m.d.Truncate(places))
+}
+
+// Float64 is the nearest float of the amount, it is only meant for
+// comparisons which tolerate rounding such as request limits.
+func (m Money) Float64() float64 {
+	return m.d.InexactFloat64()
+}
+
+func (m Money) String() string {
+	return m.d.String()
+}
+
+// MarshalJSON encodes the amount as a string to avoid float rounding by clients.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts both "12.34" and 12.34, the latter is parsed
+// from its literal so no float rounding happens.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return errInvalidMoney
+	}
+
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	value, err := NewMoney(string(data))
+	if err != nil {
+		return err
+	}
+
+	*m = value
+	return nil
+}
+
+// Scan implements sql.Scanner for numeric columns.
+func (m *Money) Scan(value interface{}) error {
+	return m.d.Scan(value)
+}
+
+// Value implements driver.Valuer for numeric columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.d.String(), nil
+}
+
+// GormDataType tells gorm to treat Money as a plain column.
+func (Money) GormDataType() string {
+	return "numeric"
+}
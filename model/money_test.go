@@ -0,0 +1,64 @@
+package model_test
+
+import (
+	"encoding/json"
+
+	. "github.com/sysdevguru/bluelabs/model"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("Money", func() {
+	Context("Arithmetic", func() {
+		It("does not drift", func() {
+			sum := Money{}
+			for i := 0; i < 10; i++ {
+				sum = sum.Add(RequireMoney("0.1"))
+			}
+			assert.True(GinkgoT(), sum.Equal(RequireMoney("1")))
+			assert.Equal(GinkgoT(), "1", sum.String())
+		})
+
+		It("compares amounts", func() {
+			assert.True(GinkgoT(), RequireMoney("9.99").LessThan(RequireMoney("10")))
+			assert.True(GinkgoT(), RequireMoney("10.00").Equal(RequireMoney("10")))
+			assert.True(GinkgoT(), RequireMoney("-0.01").IsNegative())
+			assert.True(GinkgoT(), Money{}.IsZero())
+		})
+
+		It("checks the scale", func() {
+			assert.True(GinkgoT(), RequireMoney("1.10").HasScale(2))
+			assert.True(GinkgoT(), RequireMoney("1.100").HasScale(2))
+			assert.False(GinkgoT(), RequireMoney("1.001").HasScale(2))
+		})
+	})
+
+	Context("JSON", func() {
+		It("encodes as string", func() {
+			data, err := json.Marshal(Wallet{ID: 1, UserID: 2, Type: WalletCash, Currency: "EUR", Balance: RequireMoney("10.50"), Status: WalletActive, Version: 1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), `{"id":1,"user_id":2,"type":"cash","currency":"EUR","balance":"10.5","held":"0","status":"active","version":1,"available":"10.5"}`, string(data))
+		})
+
+		It("decodes strings and numbers", func() {
+			transaction := Transaction{}
+			err := json.Unmarshal([]byte(`{"action":"deposit","fund":"0.10"}`), &transaction)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "0.1", transaction.Fund.String())
+
+			err = json.Unmarshal([]byte(`{"action":"deposit","fund":0.3}`), &transaction)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "0.3", transaction.Fund.String())
+		})
+
+		It("rejects invalid amounts", func() {
+			transaction := Transaction{}
+			err := json.Unmarshal([]byte(`{"action":"deposit","fund":"ten"}`), &transaction)
+			assert.Error(GinkgoT(), err)
+
+			err = json.Unmarshal([]byte(`{"action":"deposit","fund":null}`), &transaction)
+			assert.Error(GinkgoT(), err)
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEventType is the kind of a domain event.
+type OutboxEventType string
+
+const (
+	// OutboxBalanceChanged events hold a BalanceEvent.
+	OutboxBalanceChanged OutboxEventType = "balance_changed"
+	// OutboxStatusChanged events hold a WalletStatusChange.
+	OutboxStatusChanged OutboxEventType = "status_changed"
+)
+
+// OutboxEvent is a domain event saved in the transaction of the change it
+// describes, so it is published if and only if the change is committed.
+// Events can be published more than once, consumers drop them by id.
+type OutboxEvent struct {
+	ID        int64           `json:"id"`
+	WalletID  int64           `json:"wallet_id"`
+	Type      OutboxEventType `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// TableName maps OutboxEvent to the outbox table.
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+// NewOutboxEvent is the event of eventType of a wallet with payload as JSON.
+func NewOutboxEvent(walletID int64, eventType OutboxEventType, payload interface{}) (OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+
+	return OutboxEvent{
+		WalletID: walletID,
+		Type:     eventType,
+		Payload:  data,
+	}, nil
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateScale is the number of decimal places kept for exchange rates.
+const RateScale = 10
+
+var errInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exchange rate, the amount of the quote currency one
+// unit of the base currency is worth.
+type Rate struct {
+	d decimal.Decimal
+}
+
+// NewRate parses a positive rate such as "1.1734".
+func NewRate(value string) (Rate, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil || !d.IsPositive() {
+		return Rate{}, errInvalidRate
+	}
+
+	return Rate{d.Round(RateScale)}, nil
+}
+
+// RequireRate parses a rate and panics when it is invalid.
+func RequireRate(value string) Rate {
+	r, err := NewRate(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// OneRate converts a currency to itself.
+func OneRate() Rate {
+	return Rate{decimal.New(1, 0)}
+}
+
+// Cross returns the rate from r's quote currency to o's quote currency
+// when both rates have the same base currency.
+func (r Rate) Cross(o Rate) Rate {
+	return Rate{o.d.DivRound(r.d, RateScale)}
+}
+
+// Convert returns m in the quote currency, unrounded.
+func (r Rate) Convert(m Money) Money {
+	return Money{m.d.Mul(r.d)}
+}
+
+func (r Rate) IsZero() bool {
+	return r.d.IsZero()
+}
+
+func (r Rate) String() string {
+	return r.d.String()
+}
+
+// MarshalJSON encodes the rate as a string to avoid float rounding by clients.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + r.d.String() + `"`), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	value, err := NewRate(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = value
+	return nil
+}
+
+// Scan implements sql.Scanner for numeric columns.
+func (r *Rate) Scan(value interface{}) error {
+	return r.d.Scan(value)
+}
+
+// Value implements driver.Valuer for numeric columns.
+func (r Rate) Value() (driver.Value, error) {
+	return r.d.String(), nil
+}
+
+// GormDataType tells gorm to treat Rate as a plain column.
+func (Rate) GormDataType() string {
+	return "numeric"
+}
+
+// Quote locks an exchange rate until it expires or is used by a transfer.
+type Quote struct {
+	ID           int64      `json:"id"`
+	FromCurrency Currency   `json:"from_currency"`
+	ToCurrency   Currency   `json:"to_currency"`
+	Rate         Rate       `json:"rate"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// QuoteRequest is the payload to quote an exchange rate.
+type QuoteRequest struct {
+	FromCurrency Currency `json:"from_currency" validate:"required,currency"`
+	ToCurrency   Currency `json:"to_currency" validate:"required,currency"`
+}
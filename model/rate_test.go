@@ -0,0 +1,28 @@
+package model_test
+
+import (
+	. "github.com/sysdevguru/bluelabs/model"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("Rate", func() {
+	It("rejects non-positive rates", func() {
+		_, err := NewRate("0")
+		assert.Error(GinkgoT(), err)
+
+		_, err = NewRate("-1.5")
+		assert.Error(GinkgoT(), err)
+	})
+
+	It("converts money", func() {
+		assert.Equal(GinkgoT(), "11.5", RequireRate("11.5").Convert(RequireMoney("1")).String())
+		assert.Equal(GinkgoT(), "1.2345", RequireRate("0.5").Convert(RequireMoney("2.469")).String())
+	})
+
+	It("crosses two rates of the same base", func() {
+		assert.Equal(GinkgoT(), "9.2", RequireRate("1.25").Cross(RequireRate("11.5")).String())
+		assert.Equal(GinkgoT(), "0.3333333333", RequireRate("3").Cross(OneRate()).String())
+	})
+})
@@ -3,11 +3,15 @@ package model
 type ActionValue string
 
 const (
-	ActionDeposit  ActionValue = "deposit"
-	ActionWithdraw ActionValue = "withdraw"
+	ActionDeposit     ActionValue = "deposit"
+	ActionWithdraw    ActionValue = "withdraw"
+	ActionTransferIn  ActionValue = "transfer_in"
+	ActionTransferOut ActionValue = "transfer_out"
+	ActionCapture     ActionValue = "capture"
 )
 
 type Transaction struct {
-	Action ActionValue `json:"action" validate:"required,oneof='deposit''withdraw'"`
-	Fund   float64     `json:"fund" validate:"required,gte=0"`
+	Action   ActionValue `json:"action" validate:"required,oneof='deposit''withdraw'"`
+	Fund     Money       `json:"fund" validate:"required,gt=0,max=1000000000000,scale=Currency"`
+	Currency Currency    `json:"currency" validate:"required,currency"`
 }
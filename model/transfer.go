@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// TransferRequest is the payload of a transfer between two wallets.
+// Amount is in Currency, the currency of the source wallet. ToCurrency is
+// the currency of the target wallet and defaults to Currency, the amount
+// is converted with the rate of QuoteID or the current rate when they differ.
+type TransferRequest struct {
+	FromWalletID int64    `json:"from_wallet_id" validate:"required,gt=0"`
+	ToWalletID   int64    `json:"to_wallet_id" validate:"required,gt=0"`
+	Amount       Money    `json:"amount" validate:"required,gt=0,max=1000000000000,scale=Currency"`
+	Currency     Currency `json:"currency" validate:"required,currency"`
+	ToCurrency   Currency `json:"to_currency,omitempty" validate:"omitempty,currency"`
+	QuoteID      *int64   `json:"quote_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// Transfer is a completed move of funds between two wallets.
+type Transfer struct {
+	ID           int64     `json:"id"`
+	FromWalletID int64     `json:"from_wallet_id"`
+	ToWalletID   int64     `json:"to_wallet_id"`
+	Amount       Money     `json:"amount"`
+	Currency     Currency  `json:"currency"`
+	ToAmount     Money     `json:"to_amount"`
+	ToCurrency   Currency  `json:"to_currency"`
+	Rate         Rate      `json:"rate"`
+	QuoteID      *int64    `json:"quote_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	FromWallet *Wallet `json:"from_wallet,omitempty" gorm:"-"`
+	ToWallet   *Wallet `json:"to_wallet,omitempty" gorm:"-"`
+}
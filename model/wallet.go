@@ -1,7 +1,123 @@
 package model
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// WalletType separates the funds of a user by how they may be used.
+type WalletType string
+
+const (
+	WalletCash           WalletType = "cash"
+	WalletBonus          WalletType = "bonus"
+	WalletLockedWinnings WalletType = "locked_winnings"
+)
+
+func (t WalletType) Valid() bool {
+	switch t {
+	case WalletCash, WalletBonus, WalletLockedWinnings:
+		return true
+	}
+
+	return false
+}
+
+// WalletStatus tells which balance changes a wallet accepts.
+type WalletStatus string
+
+const (
+	WalletActive          WalletStatus = "active"
+	WalletWithdrawBlocked WalletStatus = "withdraw_blocked"
+	WalletFrozen          WalletStatus = "frozen"
+	WalletClosed          WalletStatus = "closed"
+)
+
+func (s WalletStatus) Valid() bool {
+	switch s {
+	case WalletActive, WalletWithdrawBlocked, WalletFrozen, WalletClosed:
+		return true
+	}
+
+	return false
+}
+
+// WalletGuard rejects a change of a locked wallet, debit tells whether
+// funds leave the wallet.
+type WalletGuard func(wallet *Wallet, debit bool) error
+
 type Wallet struct {
-	ID      int64   `json:"id"`
-	UserID  int64   `json:"user_id"`
-	Balance float64 `json:"balance"`
+	ID       int64      `json:"id"`
+	UserID   int64      `json:"user_id"`
+	Type     WalletType `json:"type"`
+	Currency Currency   `json:"currency"`
+	Balance  Money      `json:"balance"`
+	Held     Money      `json:"held"`
+
+	Status       WalletStatus `json:"status"`
+	StatusReason string       `json:"status_reason,omitempty"`
+
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	CloseReason string     `json:"close_reason,omitempty"`
+
+	// Version is incremented by every change of the wallet.
+	Version int64 `json:"version"`
+
+	// Entries and StatusChanges are recorded by the change which
+	// returned the wallet.
+	Entries       []LedgerEntry        `json:"-" gorm:"-"`
+	StatusChanges []WalletStatusChange `json:"-" gorm:"-"`
+}
+
+// Closed reports whether the wallet was closed, a closed wallet keeps its
+// ledger but accepts no more balance changes.
+func (w Wallet) Closed() bool {
+	return w.Status == WalletClosed
+}
+
+// Available is the part of the balance which is not reserved by holds.
+func (w Wallet) Available() Money {
+	return w.Balance.Sub(w.Held)
+}
+
+// MarshalJSON adds the available balance to the encoded wallet.
+func (w Wallet) MarshalJSON() ([]byte, error) {
+	type wallet Wallet
+
+	return json.Marshal(struct {
+		wallet
+		Available Money `json:"available"`
+	}{
+		wallet(w),
+		w.Available(),
+	})
+}
+
+// WalletStatusChange records a change of the status of a wallet.
+type WalletStatusChange struct {
+	ID         int64        `json:"id"`
+	WalletID   int64        `json:"wallet_id"`
+	FromStatus WalletStatus `json:"from_status"`
+	ToStatus   WalletStatus `json:"to_status"`
+	Reason     string       `json:"reason"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// WalletStatusRequest is the payload to change the status of a wallet.
+type WalletStatusRequest struct {
+	Status WalletStatus `json:"status" validate:"required,oneof=active withdraw_blocked frozen"`
+	Reason string       `json:"reason" validate:"required,max=255"`
+}
+
+// CloseWalletRequest is the payload to close a wallet. The remaining
+// balance is moved to SweepWalletID, without it the balance must be zero.
+type CloseWalletRequest struct {
+	Reason        string `json:"reason,omitempty" validate:"max=255"`
+	SweepWalletID *int64 `json:"sweep_wallet_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// CreateWalletRequest is the payload to create a wallet, Type defaults to cash.
+type CreateWalletRequest struct {
+	Type     WalletType `json:"type,omitempty" validate:"omitempty,oneof=cash bonus locked_winnings"`
+	Currency Currency   `json:"currency" validate:"required,currency"`
 }
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errInvalidEventTypes = errors.New("invalid event types")
+
+// WalletEventType is the kind of a change partners can be notified of.
+type WalletEventType string
+
+const (
+	EventDeposit       WalletEventType = "wallet.deposit"
+	EventWithdraw      WalletEventType = "wallet.withdraw"
+	EventStatusChanged WalletEventType = "wallet.status_changed"
+)
+
+func (t WalletEventType) Valid() bool {
+	switch t {
+	case EventDeposit, EventWithdraw, EventStatusChanged:
+		return true
+	}
+
+	return false
+}
+
+// WalletEvent is a committed change of a wallet. Entry is set for balance
+// changes and StatusChange for status changes.
+type WalletEvent struct {
+	Type         WalletEventType     `json:"type"`
+	Wallet       Wallet              `json:"wallet"`
+	Entry        *LedgerEntry        `json:"entry,omitempty"`
+	StatusChange *WalletStatusChange `json:"status_change,omitempty"`
+}
+
+// WebhookEvent is the payload delivered to the webhooks, its id is the
+// same for every webhook so partners can drop duplicates.
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	WalletEvent
+}
+
+// WalletEventTypes are the event types of a webhook, stored as a comma
+// separated list.
+type WalletEventTypes []WalletEventType
+
+// Has reports whether eventType is one of the types.
+func (t WalletEventTypes) Has(eventType WalletEventType) bool {
+	for _, value := range t {
+		if value == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Scan implements sql.Scanner for text columns.
+func (t *WalletEventTypes) Scan(value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return errInvalidEventTypes
+	}
+
+	*t = WalletEventTypes{}
+	for _, eventType := range strings.Split(text, ",") {
+		*t = append(*t, WalletEventType(eventType))
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer for text columns.
+func (t WalletEventTypes) Value() (driver.Value, error) {
+	values := make([]string, 0, len(t))
+	for _, eventType := range t {
+		values = append(values, string(eventType))
+	}
+
+	return strings.Join(values, ","), nil
+}
+
+// GormDataType tells gorm to treat WalletEventTypes as a plain column.
+func (WalletEventTypes) GormDataType() string {
+	return "text"
+}
+
+// Webhook is a partner endpoint notified of the events of its types.
+// Secret signs the payloads, it is only returned when the webhook is created.
+type Webhook struct {
+	ID        int64            `json:"id"`
+	URL       string           `json:"url"`
+	Events    WalletEventTypes `json:"events"`
+	Secret    string           `json:"secret,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// WebhookRequest is the payload to register a webhook.
+type WebhookRequest struct {
+	URL    string            `json:"url" validate:"required,url,max=2048"`
+	Events []WalletEventType `json:"events" validate:"required,min=1,dive,oneof=wallet.deposit wallet.withdraw wallet.status_changed"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead is a delivery which failed every attempt, it is only
+	// sent again when replayed.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+func (s DeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryPending, DeliveryDelivered, DeliveryDead:
+		return true
+	}
+
+	return false
+}
+
+// WebhookDelivery is an event queued for a webhook with the outcome of
+// its last attempt.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      WalletEventType `json:"event_type"`
+	Payload        []byte          `json:"-"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+
+	Webhook *Webhook `json:"-" gorm:"-"`
+}
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -20,6 +21,9 @@ type Server struct {
 	// Port is the HTTP server port.
 	Port int `envconfig:"PORT" default:"8080"`
 
+	// GRPCPort is the port of the gRPC server.
+	GRPCPort int `envconfig:"GRPC_PORT" default:"9090"`
+
 	// ReadTimeout is the maximum duration for reading the entire request,
 	// including the body.
 	ReadTimeout time.Duration `envconfig:"HTTP_SERVER_READ_TIMEOUT" default:"1s"`
@@ -27,6 +31,10 @@ type Server struct {
 	// WriteTimeout is the maximum duration before timing out
 	// writes of the response.
 	WriteTimeout time.Duration `envconfig:"HTTP_SERVER_WRITE_TIMEOUT" default:"2s"`
+
+	// OpenAPIValidation checks requests and responses against the OpenAPI
+	// document. It buffers every response, do not enable it in production.
+	OpenAPIValidation bool `envconfig:"OPENAPI_VALIDATION" default:"false"`
 }
 
 // Database contains configuration for the Postgres Database.
@@ -34,12 +42,217 @@ type Database struct {
 	URL                string `envconfig:"DATABASE_URL" required:"true"`
 	LogLevel           string `envconfig:"DATABASE_LOG_LEVEL" default:"warn"`
 	MaxOpenConnections int    `envconfig:"DATABASE_MAX_OPEN_CONNECTIONS" default:"10"`
+
+	// Locking is how deposits and withdrawals guard a wallet against
+	// concurrent changes: "pessimistic" locks its row, "optimistic" checks
+	// its version when saving and retries up to OptimisticRetries times.
+	Locking           string `envconfig:"DATABASE_LOCKING" default:"pessimistic"`
+	OptimisticRetries int    `envconfig:"DATABASE_OPTIMISTIC_RETRIES" default:"3"`
+
+	// TxRetries is how often a transaction failing on a serialization
+	// failure or a deadlock is run again, after a jittered backoff starting
+	// at up to TxBackoff.
+	TxRetries int           `envconfig:"DATABASE_TX_RETRIES" default:"3"`
+	TxBackoff time.Duration `envconfig:"DATABASE_TX_BACKOFF" default:"20ms"`
+
+	// ReplicaURL is a read replica for the wallet reads, they go to the
+	// primary when it is empty.
+	ReplicaURL string `envconfig:"DATABASE_REPLICA_URL"`
+
+	// ReplicaMaxStaleness is how far the replica may lag behind the primary
+	// for a read which does not bound its staleness itself.
+	ReplicaMaxStaleness time.Duration `envconfig:"DATABASE_REPLICA_MAX_STALENESS" default:"1s"`
+}
+
+// Events contains configuration for the balance event streams.
+type Events struct {
+	// HeartbeatInterval is how often an idle stream is kept alive.
+	HeartbeatInterval time.Duration `envconfig:"EVENTS_HEARTBEAT_INTERVAL" default:"15s"`
+
+	// Buffer is the number of events a stream holds for a slow client,
+	// the stream is closed when it is full.
+	Buffer int `envconfig:"EVENTS_BUFFER" default:"64"`
+}
+
+// Executor contains configuration for the in-process wallet executor.
+type Executor struct {
+	// Enabled funnels the deposits and withdrawals through the executor.
+	Enabled bool `envconfig:"EXECUTOR_ENABLED" default:"false"`
+
+	// Shards is the number of goroutines changing wallets, each takes a
+	// database connection at most. It should be below the open connections.
+	Shards int `envconfig:"EXECUTOR_SHARDS" default:"8"`
+
+	// QueueDepth is the number of changes waiting for a shard.
+	QueueDepth int `envconfig:"EXECUTOR_QUEUE_DEPTH" default:"256"`
+
+	// MaxBatch is the number of deposits applied in a transaction at most.
+	MaxBatch int `envconfig:"EXECUTOR_MAX_BATCH" default:"64"`
+}
+
+func (e Executor) validate() error {
+	switch {
+	case e.Shards <= 0:
+		return fmt.Errorf("EXECUTOR_SHARDS must be positive, got %d", e.Shards)
+	case e.QueueDepth < 0:
+		return fmt.Errorf("EXECUTOR_QUEUE_DEPTH must not be negative, got %d", e.QueueDepth)
+	case e.MaxBatch <= 0:
+		return fmt.Errorf("EXECUTOR_MAX_BATCH must be positive, got %d", e.MaxBatch)
+	}
+
+	return nil
+}
+
+// Holds contains configuration for the holds on wallet funds.
+type Holds struct {
+	// SweepInterval is how often the expired holds are released.
+	SweepInterval time.Duration `envconfig:"HOLDS_SWEEP_INTERVAL" default:"1m"`
+}
+
+// Idempotency contains configuration for the Idempotency-Key support.
+type Idempotency struct {
+	// Retention is how long a key and its stored response are kept.
+	Retention time.Duration `envconfig:"IDEMPOTENCY_RETENTION" default:"24h"`
+
+	// PurgeInterval is how often the expired keys are removed.
+	PurgeInterval time.Duration `envconfig:"IDEMPOTENCY_PURGE_INTERVAL" default:"1h"`
+
+	// LockTimeout is how long a request which did not complete holds its
+	// key, a retry after it takes the key over.
+	LockTimeout time.Duration `envconfig:"IDEMPOTENCY_LOCK_TIMEOUT" default:"1m"`
+}
+
+// Outbox contains configuration for the relay of the domain events.
+type Outbox struct {
+	// Publisher is where the events are relayed to, "log" or "file".
+	Publisher string `envconfig:"OUTBOX_PUBLISHER" default:"log"`
+
+	// File is the JSON lines file of the "file" publisher.
+	File string `envconfig:"OUTBOX_FILE" default:"outbox.jsonl"`
+
+	// PollInterval is how often the outbox is drained.
+	PollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL" default:"1s"`
+
+	// BatchSize is the number of events published at once.
+	BatchSize int `envconfig:"OUTBOX_BATCH_SIZE" default:"100"`
+}
+
+// Rates contains configuration for the currency exchange rates.
+type Rates struct {
+	// Base is the currency the rates are quoted against.
+	Base string `envconfig:"RATES_BASE" default:"EUR"`
+
+	// Static are the rates against Base, e.g. "USD:1.08,GBP:0.86".
+	// They are used when File is not set.
+	Static map[string]string `envconfig:"RATES_STATIC"`
+
+	// File is a JSON file of rates which is reloaded when it changes.
+	File string `envconfig:"RATES_FILE"`
+
+	// ReloadInterval is how often File is checked for changes.
+	ReloadInterval time.Duration `envconfig:"RATES_RELOAD_INTERVAL" default:"1m"`
+
+	// QuoteTTL is how long a quoted rate is locked for.
+	QuoteTTL time.Duration `envconfig:"RATES_QUOTE_TTL" default:"30s"`
+}
+
+// Tasks contains configuration for the worker pools of the wallet tasks.
+type Tasks struct {
+	// Workers is the number of tasks of a name run at once.
+	Workers int `envconfig:"TASKS_WORKERS" default:"8"`
+
+	// QueueDepth is the number of tasks of a name waiting for a worker,
+	// more are rejected.
+	QueueDepth int `envconfig:"TASKS_QUEUE_DEPTH" default:"100"`
+
+	// Timeout bounds a task, from its request for a synchronous one and
+	// from its start for a job. It should be below the WriteTimeout.
+	Timeout time.Duration `envconfig:"TASKS_TIMEOUT" default:"1500ms"`
+
+	// The limits of single tasks by name, e.g. "wallet_task.deposit:16".
+	TaskWorkers    map[string]int           `envconfig:"TASKS_TASK_WORKERS"`
+	TaskQueueDepth map[string]int           `envconfig:"TASKS_TASK_QUEUE_DEPTH"`
+	TaskTimeout    map[string]time.Duration `envconfig:"TASKS_TASK_TIMEOUT"`
+
+	// JobRetention is how long the outcome of a job can be looked up.
+	JobRetention time.Duration `envconfig:"TASKS_JOB_RETENTION" default:"10m"`
+}
+
+func (t Tasks) validate() error {
+	if t.Workers <= 0 {
+		return fmt.Errorf("TASKS_WORKERS must be positive, got %d", t.Workers)
+	}
+	if t.QueueDepth < 0 {
+		return fmt.Errorf("TASKS_QUEUE_DEPTH must not be negative, got %d", t.QueueDepth)
+	}
+
+	for task, workers := range t.TaskWorkers {
+		if workers <= 0 {
+			return fmt.Errorf("TASKS_TASK_WORKERS of %s must be positive, got %d", task, workers)
+		}
+	}
+	for task, depth := range t.TaskQueueDepth {
+		if depth < 0 {
+			return fmt.Errorf("TASKS_TASK_QUEUE_DEPTH of %s must not be negative, got %d", task, depth)
+		}
+	}
+
+	return nil
+}
+
+func (t Tasks) workers(task string) int {
+	if workers, ok := t.TaskWorkers[task]; ok {
+		return workers
+	}
+
+	return t.Workers
+}
+
+func (t Tasks) queueDepth(task string) int {
+	if depth, ok := t.TaskQueueDepth[task]; ok {
+		return depth
+	}
+
+	return t.QueueDepth
+}
+
+func (t Tasks) timeout(task string) time.Duration {
+	if timeout, ok := t.TaskTimeout[task]; ok {
+		return timeout
+	}
+
+	return t.Timeout
+}
+
+// Webhooks contains configuration for the outgoing webhooks.
+type Webhooks struct {
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration `envconfig:"WEBHOOKS_TIMEOUT" default:"5s"`
+
+	// RetryBase is the delay before retrying a failed delivery, it doubles
+	// after every failed attempt up to RetryMax.
+	RetryBase time.Duration `envconfig:"WEBHOOKS_RETRY_BASE" default:"10s"`
+	RetryMax  time.Duration `envconfig:"WEBHOOKS_RETRY_MAX" default:"1h"`
+
+	// MaxAttempts is the number of attempts before a delivery is dead.
+	MaxAttempts int `envconfig:"WEBHOOKS_MAX_ATTEMPTS" default:"10"`
+
+	// PollInterval is how often the due deliveries are sent.
+	PollInterval time.Duration `envconfig:"WEBHOOKS_POLL_INTERVAL" default:"5s"`
 }
 
 // Config is the global config struct.
 type Config struct {
-	Database Database
-	Server   Server
+	Database    Database
+	Events      Events
+	Executor    Executor
+	Holds       Holds
+	Idempotency Idempotency
+	Outbox      Outbox
+	Rates       Rates
+	Server      Server
+	Tasks       Tasks
+	Webhooks    Webhooks
 }
 
 // Load configuration from environment.
@@ -50,5 +263,13 @@ func Load() (Config, error) {
 		return config, err
 	}
 
+	if err := config.Executor.validate(); err != nil {
+		return config, err
+	}
+
+	if err := config.Tasks.validate(); err != nil {
+		return config, err
+	}
+
 	return config, nil
 }
@@ -24,11 +24,69 @@ var _ = Describe("Config", func() {
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), 60*time.Second, cfg.Server.IdleTimeout)
 			assert.Equal(GinkgoT(), 8080, cfg.Server.Port)
+			assert.Equal(GinkgoT(), 9090, cfg.Server.GRPCPort)
 			assert.Equal(GinkgoT(), 1*time.Second, cfg.Server.ReadTimeout)
 			assert.Equal(GinkgoT(), 2*time.Second, cfg.Server.WriteTimeout)
+			assert.False(GinkgoT(), cfg.Server.OpenAPIValidation)
 			assert.Equal(GinkgoT(), "http://localhost:5432", cfg.Database.URL)
 			assert.Equal(GinkgoT(), "warn", cfg.Database.LogLevel)
 			assert.Equal(GinkgoT(), 10, cfg.Database.MaxOpenConnections)
+			assert.Equal(GinkgoT(), 15*time.Second, cfg.Events.HeartbeatInterval)
+			assert.Equal(GinkgoT(), 64, cfg.Events.Buffer)
+			assert.Equal(GinkgoT(), time.Minute, cfg.Holds.SweepInterval)
+			assert.Equal(GinkgoT(), 24*time.Hour, cfg.Idempotency.Retention)
+			assert.Equal(GinkgoT(), time.Hour, cfg.Idempotency.PurgeInterval)
+			assert.Equal(GinkgoT(), time.Minute, cfg.Idempotency.LockTimeout)
+			assert.Equal(GinkgoT(), "log", cfg.Outbox.Publisher)
+			assert.Equal(GinkgoT(), "outbox.jsonl", cfg.Outbox.File)
+			assert.Equal(GinkgoT(), time.Second, cfg.Outbox.PollInterval)
+			assert.Equal(GinkgoT(), 100, cfg.Outbox.BatchSize)
+			assert.Equal(GinkgoT(), "EUR", cfg.Rates.Base)
+			assert.Equal(GinkgoT(), "", cfg.Rates.File)
+			assert.Equal(GinkgoT(), time.Minute, cfg.Rates.ReloadInterval)
+			assert.Equal(GinkgoT(), 30*time.Second, cfg.Rates.QuoteTTL)
+			assert.Equal(GinkgoT(), 5*time.Second, cfg.Webhooks.Timeout)
+			assert.Equal(GinkgoT(), 10*time.Second, cfg.Webhooks.RetryBase)
+			assert.Equal(GinkgoT(), time.Hour, cfg.Webhooks.RetryMax)
+			assert.Equal(GinkgoT(), 10, cfg.Webhooks.MaxAttempts)
+			assert.Equal(GinkgoT(), 5*time.Second, cfg.Webhooks.PollInterval)
+			assert.Equal(GinkgoT(), "pessimistic", cfg.Database.Locking)
+			assert.Equal(GinkgoT(), 3, cfg.Database.OptimisticRetries)
+			assert.Equal(GinkgoT(), 3, cfg.Database.TxRetries)
+			assert.Equal(GinkgoT(), 20*time.Millisecond, cfg.Database.TxBackoff)
+			assert.Equal(GinkgoT(), "", cfg.Database.ReplicaURL)
+			assert.Equal(GinkgoT(), time.Second, cfg.Database.ReplicaMaxStaleness)
+			assert.Equal(GinkgoT(), false, cfg.Executor.Enabled)
+			assert.Equal(GinkgoT(), 8, cfg.Executor.Shards)
+			assert.Equal(GinkgoT(), 256, cfg.Executor.QueueDepth)
+			assert.Equal(GinkgoT(), 64, cfg.Executor.MaxBatch)
+			assert.Equal(GinkgoT(), 8, cfg.Tasks.Workers)
+			assert.Equal(GinkgoT(), 100, cfg.Tasks.QueueDepth)
+			assert.Equal(GinkgoT(), 1500*time.Millisecond, cfg.Tasks.Timeout)
+			assert.Equal(GinkgoT(), 10*time.Minute, cfg.Tasks.JobRetention)
+		})
+
+		It("with invalid worker pools", func() {
+			os.Setenv("DATABASE_URL", "http://localhost:5432")
+
+			for key, value := range map[string]string{
+				"EXECUTOR_SHARDS":        "0",
+				"EXECUTOR_QUEUE_DEPTH":   "-1",
+				"EXECUTOR_MAX_BATCH":     "0",
+				"TASKS_WORKERS":          "0",
+				"TASKS_QUEUE_DEPTH":      "-1",
+				"TASKS_TASK_WORKERS":     "wallet_task.deposit:0",
+				"TASKS_TASK_QUEUE_DEPTH": "wallet_task.deposit:-1",
+			} {
+				os.Setenv(key, value)
+				_, err := Load()
+				os.Unsetenv(key)
+
+				assert.Error(GinkgoT(), err, key)
+				if err != nil {
+					assert.Contains(GinkgoT(), err.Error(), key)
+				}
+			}
 		})
 	})
 })
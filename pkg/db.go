@@ -7,8 +7,24 @@ import (
 
 // NewGormWithPostgres initializes a Postgres database connection.
 func NewGormWithPostgres(cfg Config) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{
-		Logger: nil,
+	return openPostgres(cfg.Database.URL, cfg.Database.MaxOpenConnections, false)
+}
+
+// NewGormWithPostgresReplica initializes a connection to the read replica,
+// it is nil without one. The replica is not reached until it is read, so
+// the service starts while it is down.
+func NewGormWithPostgresReplica(cfg Config) (*gorm.DB, error) {
+	if cfg.Database.ReplicaURL == "" {
+		return nil, nil
+	}
+
+	return openPostgres(cfg.Database.ReplicaURL, cfg.Database.MaxOpenConnections, true)
+}
+
+func openPostgres(url string, maxOpenConnections int, lazy bool) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{
+		Logger:               nil,
+		DisableAutomaticPing: lazy,
 	})
 	if err != nil {
 		return nil, err
@@ -19,7 +35,7 @@ func NewGormWithPostgres(cfg Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConnections)
+	sqlDB.SetMaxOpenConns(maxOpenConnections)
 
 	return db, nil
 }
@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"errors"
+
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+// constraintErrors are the domain errors of the violated constraints.
+var constraintErrors = map[string]error{
+	"user_id_type_currency_unique": walletuc.ErrWalletExists,
+	"transfer_quote_id_unique":     walletuc.ErrQuoteExpired,
+	"transfer_distinct_wallets":    walletuc.ErrSameWallet,
+	"wallet_held_within_balance":   walletuc.ErrInsufficientFunds,
+}
+
+// codeErrors are the domain errors of Postgres error codes.
+var codeErrors = map[string]error{
+	"40001": walletuc.ErrConcurrentUpdate, // serialization_failure
+	"40P01": walletuc.ErrConcurrentUpdate, // deadlock_detected
+	"55P03": walletuc.ErrConcurrentUpdate, // lock_not_available
+}
+
+// DBError is a database error translated to a domain error. It unwraps to
+// the domain error while errors.Is and errors.As still find the cause.
+type DBError struct {
+	Err   error
+	Cause *pgconn.PgError
+}
+
+func (e *DBError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DBError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DBError) Is(target error) bool {
+	return errors.Is(e.Cause, target)
+}
+
+func (e *DBError) As(target interface{}) bool {
+	return errors.As(e.Cause, target)
+}
+
+// dbError translates the Postgres errors the domain knows about,
+// other errors are returned as they are.
+func dbError(err error) error {
+	pgErr := &pgconn.PgError{}
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	if domainErr, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return &DBError{Err: domainErr, Cause: pgErr}
+	}
+	if domainErr, ok := codeErrors[pgErr.Code]; ok {
+		return &DBError{Err: domainErr, Cause: pgErr}
+	}
+
+	return err
+}
+
+// notFound returns domainErr when err is a missing record.
+func notFound(err, domainErr error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domainErr
+	}
+
+	return err
+}
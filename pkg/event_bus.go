@@ -0,0 +1,104 @@
+package pkg
+
+import (
+	"sync"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// EventBus delivers the balance events of a wallet to the subscribers of
+// the wallet within the process. Publishing never blocks: a subscriber
+// whose buffer is full is dropped and has to catch up from the ledger.
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*Subscription]struct{}
+}
+
+// Subscription receives the balance events of a wallet on Events until it
+// is closed, Events is closed when the subscription ends.
+type Subscription struct {
+	Events <-chan model.BalanceEvent
+
+	bus      *EventBus
+	walletID int64
+	events   chan model.BalanceEvent
+	lagged   bool
+	closed   bool
+}
+
+// Subscribe returns a subscription to the balance events of walletID,
+// buffer is the number of events it holds before being dropped.
+func (b *EventBus) Subscribe(walletID int64, buffer int) *Subscription {
+	events := make(chan model.BalanceEvent, buffer)
+	subscription := &Subscription{
+		Events:   events,
+		bus:      b,
+		walletID: walletID,
+		events:   events,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[walletID] == nil {
+		b.subscribers[walletID] = map[*Subscription]struct{}{}
+	}
+	b.subscribers[walletID][subscription] = struct{}{}
+
+	return subscription
+}
+
+// Publish delivers events to the subscribers of their wallets.
+func (b *EventBus) Publish(events []model.BalanceEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		for subscription := range b.subscribers[event.WalletID] {
+			select {
+			case subscription.events <- event:
+			default:
+				subscription.lagged = true
+				b.remove(subscription)
+			}
+		}
+	}
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.remove(s)
+}
+
+// Lagged reports whether the subscription was dropped because its buffer
+// was full, it is only meaningful once Events is closed.
+func (s *Subscription) Lagged() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	return s.lagged
+}
+
+// remove ends subscription, the caller holds the lock of the bus.
+func (b *EventBus) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	close(subscription.events)
+
+	subscribers := b.subscribers[subscription.walletID]
+	delete(subscribers, subscription)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, subscription.walletID)
+	}
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: map[int64]map[*Subscription]struct{}{},
+	}
+}
@@ -0,0 +1,68 @@
+package pkg_test
+
+import (
+	"github.com/sysdevguru/bluelabs/model"
+	. "github.com/sysdevguru/bluelabs/pkg"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("EventBus", func() {
+	var bus *EventBus
+
+	BeforeEach(func() {
+		bus = NewEventBus()
+	})
+
+	It("delivers the events of the wallet", func() {
+		subscription := bus.Subscribe(1, 2)
+		defer subscription.Close()
+
+		bus.Publish([]model.BalanceEvent{{ID: 1, WalletID: 1}, {ID: 2, WalletID: 2}, {ID: 3, WalletID: 1}})
+
+		assert.Equal(GinkgoT(), int64(1), (<-subscription.Events).ID)
+		assert.Equal(GinkgoT(), int64(3), (<-subscription.Events).ID)
+		assert.Len(GinkgoT(), subscription.Events, 0)
+	})
+
+	It("delivers to every subscriber", func() {
+		first, second := bus.Subscribe(1, 1), bus.Subscribe(1, 1)
+		defer first.Close()
+		defer second.Close()
+
+		bus.Publish([]model.BalanceEvent{{ID: 1, WalletID: 1}})
+
+		assert.Equal(GinkgoT(), int64(1), (<-first.Events).ID)
+		assert.Equal(GinkgoT(), int64(1), (<-second.Events).ID)
+	})
+
+	It("drops a subscriber which does not keep up", func() {
+		slow, fast := bus.Subscribe(1, 1), bus.Subscribe(1, 2)
+		defer slow.Close()
+		defer fast.Close()
+
+		bus.Publish([]model.BalanceEvent{{ID: 1, WalletID: 1}, {ID: 2, WalletID: 1}})
+
+		assert.Equal(GinkgoT(), int64(1), (<-slow.Events).ID)
+		_, open := <-slow.Events
+		assert.False(GinkgoT(), open)
+		assert.True(GinkgoT(), slow.Lagged())
+
+		assert.Equal(GinkgoT(), int64(1), (<-fast.Events).ID)
+		assert.Equal(GinkgoT(), int64(2), (<-fast.Events).ID)
+		assert.False(GinkgoT(), fast.Lagged())
+	})
+
+	It("ends a closed subscription", func() {
+		subscription := bus.Subscribe(1, 1)
+		subscription.Close()
+		subscription.Close()
+
+		bus.Publish([]model.BalanceEvent{{ID: 1, WalletID: 1}})
+
+		_, open := <-subscription.Events
+		assert.False(GinkgoT(), open)
+		assert.False(GinkgoT(), subscription.Lagged())
+	})
+})
@@ -0,0 +1,289 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Hold reserves funds of the available balance until expiresAt.
+func (g *GormRepo) Hold(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	expiresAt time.Time,
+	guard model.WalletGuard,
+) (_ *model.Hold, err error) {
+	defer func() { err = dbError(err) }()
+
+	var hold *model.Hold
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		wallet, err := lockWallet(tx, userID, walletID)
+		if err != nil {
+			return err
+		}
+
+		if err = checkWallet(wallet, guard, true); err != nil {
+			return err
+		}
+
+		if err = checkCurrency(wallet, funds, wallet.Currency); err != nil {
+			return err
+		}
+
+		if err = releaseExpiredHolds(tx, wallet); err != nil {
+			return err
+		}
+
+		if wallet.Available().LessThan(funds) {
+			return walletuc.ErrInsufficientFunds
+		}
+
+		wallet.Held = wallet.Held.Add(funds)
+		if err = saveWallet(tx, wallet); err != nil {
+			return err
+		}
+
+		hold = &model.Hold{
+			WalletID:  wallet.ID,
+			Amount:    funds,
+			Status:    model.HoldActive,
+			ExpiresAt: expiresAt,
+			Wallet:    wallet,
+		}
+
+		return tx.Create(hold).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// Capture spends funds of an active hold, or the whole hold when funds is nil.
+// The rest of the hold is released.
+func (g *GormRepo) Capture(
+	ctx context.Context,
+	userID, walletID, holdID int64,
+	funds *model.Money,
+	guard model.WalletGuard,
+) (_ *model.Hold, err error) {
+	defer func() { err = dbError(err) }()
+
+	var hold *model.Hold
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		wallet, err := lockWallet(tx, userID, walletID)
+		if err != nil {
+			return err
+		}
+
+		if err = checkWallet(wallet, guard, true); err != nil {
+			return err
+		}
+
+		hold, err = lockActiveHold(tx, wallet, holdID)
+		if err != nil {
+			return err
+		}
+
+		captured := hold.Amount
+		if funds != nil {
+			captured = *funds
+		}
+
+		if err = checkCurrency(wallet, captured, wallet.Currency); err != nil {
+			return err
+		}
+
+		if hold.Amount.LessThan(captured) {
+			return walletuc.ErrCaptureAmount
+		}
+
+		wallet.Balance = wallet.Balance.Sub(captured)
+		wallet.Held = wallet.Held.Sub(hold.Amount)
+		if err = saveWallet(tx, wallet); err != nil {
+			return err
+		}
+
+		hold.Captured = captured
+		hold.Status = model.HoldCaptured
+		if err = tx.Save(hold).Error; err != nil {
+			return err
+		}
+
+		return record(tx, wallet, model.LedgerEntry{
+			Action: model.ActionCapture,
+			Amount: captured,
+			HoldID: &hold.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// Release gives the funds of an active hold back to the available balance.
+func (g *GormRepo) Release(ctx context.Context, userID, walletID, holdID int64) (_ *model.Hold, err error) {
+	defer func() { err = dbError(err) }()
+
+	var hold *model.Hold
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		wallet, err := lockWallet(tx, userID, walletID)
+		if err != nil {
+			return err
+		}
+
+		hold, err = lockActiveHold(tx, wallet, holdID)
+		if err != nil {
+			return err
+		}
+
+		wallet.Held = wallet.Held.Sub(hold.Amount)
+		if err = saveWallet(tx, wallet); err != nil {
+			return err
+		}
+
+		hold.Status = model.HoldReleased
+		return tx.Save(hold).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (g *GormRepo) GetHold(ctx context.Context, userID, walletID, holdID int64) (_ *model.Hold, err error) {
+	defer func() { err = dbError(err) }()
+
+	db := g.db.WithContext(ctx)
+
+	wallet := &model.Wallet{}
+	result := db.Where("id=?", walletID).
+		Where("user_id=?", userID).
+		First(wallet)
+	if result.Error != nil {
+		return nil, notFound(result.Error, walletuc.ErrWalletNotFound)
+	}
+
+	hold := &model.Hold{}
+	result = db.Where("id=?", holdID).
+		Where("wallet_id=?", walletID).
+		First(hold)
+	if result.Error != nil {
+		return nil, notFound(result.Error, walletuc.ErrHoldNotFound)
+	}
+
+	return hold, nil
+}
+
+// ReleaseExpiredHolds expires the active holds which passed their expiry
+// and returns the number of wallets updated.
+func (g *GormRepo) ReleaseExpiredHolds(ctx context.Context) (_ int, err error) {
+	defer func() { err = dbError(err) }()
+
+	walletIDs := []int64{}
+	result := g.db.WithContext(ctx).
+		Model(&model.Hold{}).
+		Distinct("wallet_id").
+		Where("status=?", model.HoldActive).
+		Where("expires_at<=?", time.Now()).
+		Limit(100).
+		Pluck("wallet_id", &walletIDs)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	for i, walletID := range walletIDs {
+		if err = g.releaseExpiredHolds(ctx, walletID); err != nil {
+			return i, err
+		}
+	}
+
+	return len(walletIDs), nil
+}
+
+func (g *GormRepo) releaseExpiredHolds(ctx context.Context, walletID int64) error {
+	return g.uow.Run(ctx, func(tx *gorm.DB) error {
+		wallet := &model.Wallet{}
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id=?", walletID).
+			First(wallet)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return releaseExpiredHolds(tx, wallet)
+	})
+}
+
+func lockWallet(tx *gorm.DB, userID, walletID int64) (*model.Wallet, error) {
+	wallet := &model.Wallet{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id=?", walletID).
+		Where("user_id=?", userID).
+		First(wallet)
+	if result.Error != nil {
+		return nil, notFound(result.Error, walletuc.ErrWalletNotFound)
+	}
+
+	return wallet, nil
+}
+
+func lockActiveHold(tx *gorm.DB, wallet *model.Wallet, holdID int64) (*model.Hold, error) {
+	hold := &model.Hold{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id=?", holdID).
+		Where("wallet_id=?", wallet.ID).
+		First(hold)
+	if result.Error != nil {
+		return nil, notFound(result.Error, walletuc.ErrHoldNotFound)
+	}
+
+	if hold.Status != model.HoldActive {
+		return nil, walletuc.ErrHoldNotActive
+	}
+
+	if !hold.ExpiresAt.After(time.Now()) {
+		return nil, walletuc.ErrHoldExpired
+	}
+
+	hold.Wallet = wallet
+	return hold, nil
+}
+
+// releaseExpiredHolds expires the active holds of the locked wallet which
+// passed their expiry and gives their funds back to the available balance.
+func releaseExpiredHolds(tx *gorm.DB, wallet *model.Wallet) error {
+	holds := []model.Hold{}
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("wallet_id=?", wallet.ID).
+		Where("status=?", model.HoldActive).
+		Where("expires_at<=?", time.Now()).
+		Find(&holds)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for i := range holds {
+		wallet.Held = wallet.Held.Sub(holds[i].Amount)
+		holds[i].Status = model.HoldExpired
+		if err := tx.Save(&holds[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(holds) == 0 {
+		return nil
+	}
+
+	return saveWallet(tx, wallet)
+}
@@ -1,25 +1,42 @@
 package pkg
 
-var (
-	ErrWalletNotFound = "wallet not found"
-	ErrWalletBalance  = "wallet balance not enough"
-	ErrWalletID       = "invalid wallet id"
-	ErrUserID         = "invalid user id"
-	ErrInvalidAction  = "unavailable action"
-	ErrWalletFund     = "cannot update balance with nagetive fund"
-	ErrDuplicated     = "user already has a wallet"
+import (
+	"net/http"
+	"strings"
 )
 
+// StatusCode is the code of an HTTP status, e.g. BAD_REQUEST.
+func StatusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		text = http.StatusText(http.StatusInternalServerError)
+	}
+
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
 // HttpError represents http server error
 type HTTPError interface {
 	error
 	Status() int
 }
 
+// FieldError describes a request field which failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // StatusError represents an error with an associated HTTP status code
 type StatusError struct {
 	Code   int
 	ErrMsg string
+	// ErrCode is the stable code of the error, clients should match on it
+	// instead of the message. The code of the status is used when empty.
+	ErrCode string
+	// Fields are the request fields which failed validation.
+	Fields []FieldError
 }
 
 func (s StatusError) Error() string {
@@ -29,3 +46,23 @@ func (s StatusError) Error() string {
 func (s StatusError) Status() int {
 	return s.Code
 }
+
+// ErrorCode is the stable code of the error.
+func (s StatusError) ErrorCode() string {
+	if s.ErrCode != "" {
+		return s.ErrCode
+	}
+
+	return StatusCode(s.Code)
+}
+
+// Problem is an RFC 7807 problem details response body.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
@@ -0,0 +1,23 @@
+package pkg_test
+
+import (
+	"net/http"
+
+	. "github.com/sysdevguru/bluelabs/pkg"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("HTTP errors", func() {
+	It("have their own code", func() {
+		err := StatusError{Code: http.StatusBadRequest, ErrMsg: "wallet balance not enough", ErrCode: "INSUFFICIENT_FUNDS"}
+		assert.Equal(GinkgoT(), "INSUFFICIENT_FUNDS", err.ErrorCode())
+	})
+
+	It("fall back to the code of their status", func() {
+		err := StatusError{Code: http.StatusServiceUnavailable, ErrMsg: "dial tcp: connection refused"}
+		assert.Equal(GinkgoT(), "SERVICE_UNAVAILABLE", err.ErrorCode())
+		assert.Equal(GinkgoT(), "INTERNAL_SERVER_ERROR", StatusCode(0))
+	})
+})
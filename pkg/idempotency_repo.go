@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	idempotencyuc "github.com/sysdevguru/bluelabs/usecase/idempotency"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormIdempotencyRepo struct {
+	db *gorm.DB
+}
+
+// Reserve stores record unless its key is already taken by a record created
+// after expiredBefore, in which case the stored record is returned. A
+// reservation which did not complete is taken over once created before
+// staleBefore.
+func (g *GormIdempotencyRepo) Reserve(
+	ctx context.Context,
+	record *model.IdempotencyRecord,
+	expiredBefore, staleBefore time.Time,
+) (*model.IdempotencyRecord, error) {
+	db := g.db.WithContext(ctx)
+
+	result := db.Where("idempotency_key=?", record.Key).
+		Where("created_at<? OR (status_code=? AND created_at<?)", expiredBefore, 0, staleBefore).
+		Delete(&model.IdempotencyRecord{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	existing := &model.IdempotencyRecord{}
+	result = db.Where("idempotency_key=?", record.Key).First(existing)
+
+	return existing, result.Error
+}
+
+// Complete stores the response of the reservation of key by holder, a
+// completed record is never changed.
+func (g *GormIdempotencyRepo) Complete(ctx context.Context, key, holder string, statusCode int, response []byte) error {
+	result := g.db.WithContext(ctx).
+		Model(&model.IdempotencyRecord{}).
+		Where("idempotency_key=? AND holder=?", key, holder).
+		Where("status_code=?", 0).
+		Updates(map[string]interface{}{
+			"status_code": statusCode,
+			"response":    response,
+		})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return idempotencyuc.ErrReservationLost
+	}
+
+	return result.Error
+}
+
+// Release drops a reservation of key by holder that did not complete so the
+// request can be retried.
+func (g *GormIdempotencyRepo) Release(ctx context.Context, key, holder string) error {
+	result := g.db.WithContext(ctx).
+		Where("idempotency_key=? AND holder=?", key, holder).
+		Where("status_code=?", 0).
+		Delete(&model.IdempotencyRecord{})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return idempotencyuc.ErrReservationLost
+	}
+
+	return result.Error
+}
+
+func (g *GormIdempotencyRepo) Purge(ctx context.Context, expiredBefore time.Time) error {
+	return g.db.WithContext(ctx).
+		Where("created_at<?", expiredBefore).
+		Delete(&model.IdempotencyRecord{}).Error
+}
+
+func NewIdempotencyRepo(db *gorm.DB) *GormIdempotencyRepo {
+	return &GormIdempotencyRepo{
+		db: db,
+	}
+}
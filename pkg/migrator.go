@@ -0,0 +1,444 @@
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationLock is the advisory lock held while the schema is migrated, so
+// instances started together migrate it once.
+const migrationLock = 2
+
+// baselineVersion is the migration a schema set up by the SQL scripts
+// before the migrations is at when it has no recorded migrations, the
+// schema is detected by baselineTable.
+const (
+	baselineVersion = 10
+	baselineTable   = "wallets"
+)
+
+// migrationFile matches the names of the migration files,
+// <version>_<name>.up.sql and <version>_<name>.down.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// errors of the schema migrations
+var (
+	ErrMigrationModified = errors.New("applied migration was modified")
+	ErrUnknownMigration  = errors.New("unknown migration")
+	ErrSchemaVersion     = errors.New("schema is not at the version of the service")
+	ErrMigrationsApplied = errors.New("migrations are applied already")
+)
+
+// Migration is a versioned change of the database schema.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum identifies the up and the down SQL of the migration, it is
+// recorded when the migration is applied.
+func (m Migration) Checksum() string {
+	hash := sha256.New()
+	hash.Write([]byte(m.Up))
+	hash.Write([]byte{0})
+	hash.Write([]byte(m.Down))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// MigrationStatus is a migration and whether it was applied, an applied
+// migration which is not known is listed too.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	// Modified migrations were changed since they were applied.
+	Modified bool
+	// Unknown migrations were applied but have no files.
+	Unknown bool
+}
+
+// schemaMigration is an applied migration.
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies and reverts the migrations of the schema, each run is a
+// single transaction which leaves the schema unchanged when it fails.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// Latest is the version of the last migration, the one the schema must be
+// at for the service to run.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status lists the migrations in version order.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []MigrationStatus{}
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.Modified = record.Checksum != migration.Checksum()
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			AppliedAt: &appliedAt,
+			Unknown:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Check fails with ErrSchemaVersion unless every migration is applied as
+// it is and no other one is.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := appliedMigrations(m.db.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+
+	if len(applied) != len(m.migrations) {
+		return fmt.Errorf("%w: %d of %d migrations are applied", ErrSchemaVersion, len(applied), len(m.migrations))
+	}
+
+	return nil
+}
+
+// Up applies the migrations which are not applied yet.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the last applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.migrate(ctx, func(applied map[int64]schemaMigration) (up, down []Migration) {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				return nil, m.migrations[i : i+1]
+			}
+		}
+
+		return nil, nil
+	})
+}
+
+// To applies the migrations up to version and reverts the ones after it,
+// version 0 reverts every migration.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownMigration, version)
+	}
+
+	return m.migrate(ctx, func(applied map[int64]schemaMigration) (up, down []Migration) {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+				up = append(up, migration)
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok && m.migrations[i].Version > version {
+				down = append(down, m.migrations[i])
+			}
+		}
+
+		return up, down
+	})
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for a schema the SQL scripts before the migrations took past the
+// baseline. It fails with ErrMigrationsApplied unless none is recorded.
+func (m *Migrator) Baseline(ctx context.Context, version int64) error {
+	i := m.index(version)
+	if i < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownMigration, version)
+	}
+
+	return m.transaction(ctx, func(tx *gorm.DB, applied map[int64]schemaMigration) error {
+		if len(applied) > 0 {
+			return ErrMigrationsApplied
+		}
+
+		for _, migration := range m.migrations[:i+1] {
+			if err := recordMigration(tx, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// migrate applies the up migrations and then reverts the down ones which
+// plan returns for the applied migrations, while holding the migration lock.
+// A schema set up by the SQL scripts is at the baseline migration first.
+func (m *Migrator) migrate(
+	ctx context.Context,
+	plan func(applied map[int64]schemaMigration) (up, down []Migration),
+) error {
+	return m.transaction(ctx, func(tx *gorm.DB, applied map[int64]schemaMigration) error {
+		if len(applied) == 0 {
+			if err := m.baseline(tx, applied); err != nil {
+				return err
+			}
+		}
+
+		up, down := plan(applied)
+		for _, migration := range up {
+			if err := apply(tx, migration); err != nil {
+				return err
+			}
+		}
+		for _, migration := range down {
+			if err := revert(tx, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// baseline records the baseline migration as applied when the schema was
+// set up by the SQL scripts, which left no record of it.
+func (m *Migrator) baseline(tx *gorm.DB, applied map[int64]schemaMigration) error {
+	i := m.index(baselineVersion)
+	if i < 0 {
+		return nil
+	}
+
+	exists := false
+	if err := tx.Raw("SELECT to_regclass(?) IS NOT NULL", baselineTable).Scan(&exists).Error; err != nil || !exists {
+		return err
+	}
+
+	if err := recordMigration(tx, m.migrations[i]); err != nil {
+		return err
+	}
+	applied[baselineVersion] = schemaMigration{Version: baselineVersion, Name: m.migrations[i].Name}
+
+	return nil
+}
+
+// transaction runs change with the verified applied migrations in a single
+// transaction, while holding the migration lock.
+func (m *Migrator) transaction(
+	ctx context.Context,
+	change func(tx *gorm.DB, applied map[int64]schemaMigration) error,
+) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLock).Error; err != nil {
+			return err
+		}
+
+		err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY,
+			name varchar(255) NOT NULL,
+			checksum char(64) NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		)`).Error
+		if err != nil {
+			return err
+		}
+
+		applied, err := appliedMigrations(tx)
+		if err != nil {
+			return err
+		}
+		if err = m.verify(applied); err != nil {
+			return err
+		}
+
+		return change(tx, applied)
+	})
+}
+
+// verify fails when an applied migration is unknown or was modified.
+func (m *Migrator) verify(applied map[int64]schemaMigration) error {
+	for version, record := range applied {
+		i := m.index(version)
+		if i < 0 {
+			return fmt.Errorf("%w: %d_%s is applied", ErrUnknownMigration, version, record.Name)
+		}
+		if record.Checksum != m.migrations[i].Checksum() {
+			return fmt.Errorf("%w: %d_%s", ErrMigrationModified, version, record.Name)
+		}
+	}
+
+	return nil
+}
+
+// index is the index of the migration of version, -1 when there is none.
+func (m *Migrator) index(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func apply(tx *gorm.DB, migration Migration) error {
+	if err := tx.Exec(migration.Up).Error; err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return recordMigration(tx, migration)
+}
+
+// recordMigration records migration as applied.
+func recordMigration(tx *gorm.DB, migration Migration) error {
+	return tx.Create(&schemaMigration{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Checksum:  migration.Checksum(),
+		AppliedAt: time.Now(),
+	}).Error
+}
+
+func revert(tx *gorm.DB, migration Migration) error {
+	if err := tx.Exec(migration.Down).Error; err != nil {
+		return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return tx.Delete(&schemaMigration{}, migration.Version).Error
+}
+
+// appliedMigrations reads the applied migrations by version, there are
+// none before the first migration run created their table.
+func appliedMigrations(db *gorm.DB) (map[int64]schemaMigration, error) {
+	exists := false
+	if err := db.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists).Error; err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]schemaMigration{}
+	if !exists {
+		return applied, nil
+	}
+
+	records := []schemaMigration{}
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+// LoadMigrations reads the migrations of fsys in version order. Every
+// migration needs both its up and its down file.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, name := range names {
+		match := migrationFile.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version %s", name)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names", version)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// NewMigrator returns a migrator of the schema of db to the migrations of fsys.
+func NewMigrator(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
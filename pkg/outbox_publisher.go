@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// LogEventPublisher writes the domain events to the log.
+type LogEventPublisher struct{}
+
+func (LogEventPublisher) Publish(_ context.Context, events []model.OutboxEvent) error {
+	for _, event := range events {
+		log.Printf("event %d %s of wallet %d: %s\n", event.ID, event.Type, event.WalletID, event.Payload)
+	}
+
+	return nil
+}
+
+// FileEventPublisher appends the domain events to a file, one JSON
+// document per line.
+type FileEventPublisher struct {
+	path string
+}
+
+// Publish returns once the events are synced to the file.
+func (p *FileEventPublisher) Publish(_ context.Context, events []model.OutboxEvent) error {
+	file, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, event := range events {
+		if err = encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func NewFileEventPublisher(path string) *FileEventPublisher {
+	return &FileEventPublisher{
+		path: path,
+	}
+}
+
+// MemoryEventPublisher keeps the domain events it is given, for tests.
+type MemoryEventPublisher struct {
+	mu     sync.Mutex
+	events []model.OutboxEvent
+}
+
+func (p *MemoryEventPublisher) Publish(_ context.Context, events []model.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events returns the published events in publishing order.
+func (p *MemoryEventPublisher) Events() []model.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]model.OutboxEvent{}, p.events...)
+}
+
+func NewMemoryEventPublisher() *MemoryEventPublisher {
+	return &MemoryEventPublisher{}
+}
@@ -0,0 +1,65 @@
+package pkg_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/sysdevguru/bluelabs/model"
+	. "github.com/sysdevguru/bluelabs/pkg"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("EventPublisher", func() {
+	var (
+		ctx    context.Context
+		events []model.OutboxEvent
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		events = []model.OutboxEvent{
+			{ID: 1, WalletID: 1, Type: model.OutboxBalanceChanged, Payload: json.RawMessage(`{"id":1}`)},
+			{ID: 2, WalletID: 1, Type: model.OutboxStatusChanged, Payload: json.RawMessage(`{"id":2}`)},
+		}
+	})
+
+	It("appends the events to a file", func() {
+		dir, err := os.MkdirTemp("", "outbox")
+		assert.NoError(GinkgoT(), err)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "outbox.jsonl")
+		publisher := NewFileEventPublisher(path)
+
+		assert.NoError(GinkgoT(), publisher.Publish(ctx, events[:1]))
+		assert.NoError(GinkgoT(), publisher.Publish(ctx, events[1:]))
+
+		file, err := os.Open(path)
+		assert.NoError(GinkgoT(), err)
+		defer file.Close()
+
+		published := []model.OutboxEvent{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			event := model.OutboxEvent{}
+			assert.NoError(GinkgoT(), json.Unmarshal(scanner.Bytes(), &event))
+			published = append(published, event)
+		}
+
+		assert.Equal(GinkgoT(), events, published)
+	})
+
+	It("keeps the events in memory", func() {
+		publisher := NewMemoryEventPublisher()
+
+		assert.NoError(GinkgoT(), publisher.Publish(ctx, events[:1]))
+		assert.NoError(GinkgoT(), publisher.Publish(ctx, events[1:]))
+
+		assert.Equal(GinkgoT(), events, publisher.Events())
+	})
+})
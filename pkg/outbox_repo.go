@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/model"
+
+	"gorm.io/gorm"
+)
+
+// outboxLock is the advisory lock held by the relay of the outbox, a single
+// relay keeps the events of a wallet in order.
+const outboxLock = 1
+
+type GormOutboxRepo struct {
+	db *gorm.DB
+}
+
+// Relay locks the outbox for the transaction and deletes the events in it
+// once publish succeeds. When another relay holds the lock it returns
+// without calling publish.
+func (g *GormOutboxRepo) Relay(
+	ctx context.Context,
+	limit int,
+	publish func([]model.OutboxEvent) error,
+) (published int, err error) {
+	err = g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked := false
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		events := []model.OutboxEvent{}
+		if err := tx.Order("id").Limit(limit).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err := publish(events); err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+
+		published = len(events)
+		return tx.Delete(&model.OutboxEvent{}, ids).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
+
+func NewOutboxRepo(db *gorm.DB) *GormOutboxRepo {
+	return &GormOutboxRepo{
+		db: db,
+	}
+}
@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	"gorm.io/gorm"
+)
+
+func (g *GormRepo) CreateQuote(ctx context.Context, quote *model.Quote) error {
+	return dbError(g.db.WithContext(ctx).Create(quote).Error)
+}
+
+func (g *GormRepo) GetQuote(ctx context.Context, quoteID int64) (*model.Quote, error) {
+	quote := &model.Quote{}
+	result := g.db.WithContext(ctx).Where("id=?", quoteID).First(quote)
+	if result.Error != nil {
+		return nil, dbError(notFound(result.Error, walletuc.ErrQuoteNotFound))
+	}
+
+	return quote, nil
+}
+
+// useQuote marks a quote as used within tx, it fails when the quote
+// has expired or was used by another transfer.
+func useQuote(tx *gorm.DB, quoteID int64) error {
+	now := time.Now()
+	result := tx.Model(&model.Quote{}).
+		Where("id=?", quoteID).
+		Where("used_at IS NULL").
+		Where("expires_at>?", now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected != 1 {
+		return walletuc.ErrQuoteExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
+)
+
+// ErrRateCurrency is returned for rate tables with unknown currencies.
+var ErrRateCurrency = errors.New("invalid currency")
+
+// RateTable holds the rates of currencies against a base currency.
+type RateTable struct {
+	Base  model.Currency                `json:"base"`
+	Rates map[model.Currency]model.Rate `json:"rates"`
+}
+
+// Rate returns the cross rate from one currency to another.
+func (t RateTable) Rate(from, to model.Currency) (model.Rate, error) {
+	if from == to {
+		return model.OneRate(), nil
+	}
+
+	fromRate, ok := t.against(from)
+	if !ok {
+		return model.Rate{}, walletuc.ErrRateUnavailable
+	}
+
+	toRate, ok := t.against(to)
+	if !ok {
+		return model.Rate{}, walletuc.ErrRateUnavailable
+	}
+
+	return fromRate.Cross(toRate), nil
+}
+
+func (t RateTable) against(currency model.Currency) (model.Rate, bool) {
+	if currency == t.Base {
+		return model.OneRate(), true
+	}
+
+	rate, ok := t.Rates[currency]
+	return rate, ok
+}
+
+func (t RateTable) validate() error {
+	if !t.Base.Valid() {
+		return ErrRateCurrency
+	}
+
+	for currency := range t.Rates {
+		if !currency.Valid() {
+			return ErrRateCurrency
+		}
+	}
+
+	return nil
+}
+
+// StaticRateProvider serves rates from a fixed table.
+type StaticRateProvider struct {
+	table RateTable
+}
+
+func (p *StaticRateProvider) Rate(_ context.Context, from, to model.Currency) (model.Rate, error) {
+	return p.table.Rate(from, to)
+}
+
+// NewStaticRateProvider builds a provider from rates against base such as {"USD": "1.08"}.
+func NewStaticRateProvider(base string, rates map[string]string) (*StaticRateProvider, error) {
+	table := RateTable{
+		Base:  model.Currency(base),
+		Rates: map[model.Currency]model.Rate{},
+	}
+
+	for currency, value := range rates {
+		rate, err := model.NewRate(value)
+		if err != nil {
+			return nil, err
+		}
+		table.Rates[model.Currency(currency)] = rate
+	}
+
+	if err := table.validate(); err != nil {
+		return nil, err
+	}
+
+	return &StaticRateProvider{table}, nil
+}
+
+// FileRateProvider serves rates from a JSON encoded RateTable file.
+// The file is checked for changes at most once per interval, a file
+// which fails to load keeps the previous rates in use.
+type FileRateProvider struct {
+	path     string
+	interval time.Duration
+
+	mu        sync.RWMutex
+	table     RateTable
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func (p *FileRateProvider) Rate(_ context.Context, from, to model.Currency) (model.Rate, error) {
+	p.mu.RLock()
+	due := time.Since(p.checkedAt) >= p.interval
+	p.mu.RUnlock()
+
+	if due {
+		if err := p.Reload(); err != nil {
+			log.Println("failed to reload rates", err)
+		}
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.table.Rate(from, to)
+}
+
+// Reload reads the file again when it was modified since the last load.
+func (p *FileRateProvider) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.checkedAt = time.Now()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+
+	table := RateTable{}
+	if err := json.Unmarshal(data, &table); err != nil {
+		return err
+	}
+	if err := table.validate(); err != nil {
+		return err
+	}
+
+	p.table = table
+	p.modTime = info.ModTime()
+
+	return nil
+}
+
+// NewFileRateProvider loads the rates of path, which is reloaded every interval.
+func NewFileRateProvider(path string, interval time.Duration) (*FileRateProvider, error) {
+	provider := &FileRateProvider{
+		path:     path,
+		interval: interval,
+	}
+
+	if err := provider.Reload(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,126 @@
+package pkg_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	. "github.com/sysdevguru/bluelabs/pkg"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("Rates", func() {
+	ctx := context.Background()
+
+	Context("Static rates", func() {
+		var provider *StaticRateProvider
+
+		BeforeEach(func() {
+			var err error
+			provider, err = NewStaticRateProvider("EUR", map[string]string{
+				"USD": "1.25",
+				"SEK": "11.5",
+			})
+			assert.NoError(GinkgoT(), err)
+		})
+
+		It("from the base currency", func() {
+			rate, err := provider.Rate(ctx, "EUR", "USD")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "1.25", rate.String())
+		})
+
+		It("to the base currency", func() {
+			rate, err := provider.Rate(ctx, "USD", "EUR")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "0.8", rate.String())
+		})
+
+		It("between two other currencies", func() {
+			rate, err := provider.Rate(ctx, "USD", "SEK")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "9.2", rate.String())
+		})
+
+		It("for the same currency", func() {
+			rate, err := provider.Rate(ctx, "JPY", "JPY")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "1", rate.String())
+		})
+
+		It("without a rate", func() {
+			_, err := provider.Rate(ctx, "EUR", "JPY")
+			assert.Equal(GinkgoT(), "exchange rate not available", err.Error())
+		})
+
+		It("with an invalid rate", func() {
+			_, err := NewStaticRateProvider("EUR", map[string]string{"USD": "-1"})
+			assert.Error(GinkgoT(), err)
+		})
+
+		It("with an invalid currency", func() {
+			_, err := NewStaticRateProvider("EUR", map[string]string{"XXX": "1"})
+			assert.ErrorIs(GinkgoT(), err, ErrRateCurrency)
+		})
+	})
+
+	Context("File rates", func() {
+		var path string
+
+		// GinkgoT().TempDir() is empty in ginkgo v1, the file would be
+		// written to the package directory
+		BeforeEach(func() {
+			dir, err := os.MkdirTemp("", "rates")
+			assert.NoError(GinkgoT(), err)
+
+			path = filepath.Join(dir, "rates.json")
+			err = os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"USD": "1.25"}}`), 0o600)
+			assert.NoError(GinkgoT(), err)
+		})
+
+		AfterEach(func() {
+			assert.NoError(GinkgoT(), os.RemoveAll(filepath.Dir(path)))
+		})
+
+		It("without the file", func() {
+			_, err := NewFileRateProvider(filepath.Join(filepath.Dir(path), "missing.json"), time.Minute)
+			assert.Error(GinkgoT(), err)
+		})
+
+		It("reloads a changed file", func() {
+			provider, err := NewFileRateProvider(path, 0)
+			assert.NoError(GinkgoT(), err)
+
+			rate, err := provider.Rate(ctx, "EUR", "USD")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "1.25", rate.String())
+
+			err = os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"USD": "1.5"}}`), 0o600)
+			assert.NoError(GinkgoT(), err)
+			err = os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+			assert.NoError(GinkgoT(), err)
+
+			rate, err = provider.Rate(ctx, "EUR", "USD")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "1.5", rate.String())
+		})
+
+		It("keeps the rates of an invalid file", func() {
+			provider, err := NewFileRateProvider(path, 0)
+			assert.NoError(GinkgoT(), err)
+
+			err = os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"USD": "abc"}}`), 0o600)
+			assert.NoError(GinkgoT(), err)
+			err = os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+			assert.NoError(GinkgoT(), err)
+
+			rate, err := provider.Rate(ctx, "EUR", "USD")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.RequireRate("1.25"), rate)
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// replicaLagTTL is how long a measured replica lag is used for.
+const replicaLagTTL = time.Second
+
+// errStaleRead fails a replica read which is older than the request allows.
+var errStaleRead = errors.New("replica read is stale")
+
+// ReadConsistency bounds how stale the reads of a request may be.
+type ReadConsistency struct {
+	// MaxStaleness is how far behind the primary a replica may be, the
+	// primary is read when it is 0.
+	MaxStaleness time.Duration
+	// MinVersion is the wallet version a read of a wallet must see.
+	MinVersion int64
+}
+
+type readConsistencyKey struct{}
+
+// WithReadConsistency bounds the staleness of the reads made with ctx,
+// instead of the staleness allowed by the repo.
+func WithReadConsistency(ctx context.Context, consistency ReadConsistency) context.Context {
+	return context.WithValue(ctx, readConsistencyKey{}, consistency)
+}
+
+// replica is a read replica of the database with its last measured lag.
+type replica struct {
+	db           *gorm.DB
+	maxStaleness time.Duration
+
+	mu         sync.Mutex
+	lag        time.Duration
+	lagErr     error
+	measuredAt time.Time
+}
+
+// consistency is the read consistency of ctx, the default one of the
+// replica when it has none.
+func (r *replica) consistency(ctx context.Context) ReadConsistency {
+	if consistency, ok := ctx.Value(readConsistencyKey{}).(ReadConsistency); ok {
+		return consistency
+	}
+
+	return ReadConsistency{MaxStaleness: r.maxStaleness}
+}
+
+// fresh tells whether the replica lags no more than maxStaleness behind
+// the primary, a replica whose lag cannot be measured is not.
+func (r *replica) fresh(ctx context.Context, maxStaleness time.Duration) bool {
+	if maxStaleness <= 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.measuredAt) > replicaLagTTL {
+		r.lag, r.lagErr = r.measureLag(ctx)
+		r.measuredAt = time.Now()
+	}
+
+	return r.lagErr == nil && r.lag <= maxStaleness
+}
+
+// measureLag is the age of the last transaction the replica replayed, a
+// server which is not in recovery is the primary itself and has no lag.
+func (r *replica) measureLag(ctx context.Context) (time.Duration, error) {
+	status := struct {
+		Recovery bool
+		Lag      *float64
+	}{}
+
+	err := r.db.WithContext(ctx).Raw(`SELECT pg_is_in_recovery() AS recovery,
+		EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) AS lag`).Scan(&status).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if !status.Recovery {
+		return 0, nil
+	}
+	if status.Lag == nil {
+		return 0, errStaleRead
+	}
+
+	return time.Duration(*status.Lag * float64(time.Second)), nil
+}
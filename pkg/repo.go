@@ -3,8 +3,10 @@ package pkg
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/sysdevguru/bluelabs/model"
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -12,70 +14,737 @@ import (
 
 type GormRepo struct {
 	db *gorm.DB
+
+	// uow runs the changes of the repo within their transactions.
+	uow *UnitOfWork
+
+	// optimistic repos read the wallets they change without locking them
+	// and retry a change up to retries times when it conflicts.
+	optimistic bool
+	retries    int
+
+	// replica serves the reads which may be stale, it is nil without one.
+	replica *replica
 }
 
-func (g *GormRepo) Create(ctx context.Context, userID int64) (*model.Wallet, error) {
+func (g *GormRepo) Create(
+	ctx context.Context,
+	userID int64,
+	walletType model.WalletType,
+	currency model.Currency,
+) (_ *model.Wallet, err error) {
+	defer func() { err = dbError(err) }()
+
 	wallet := &model.Wallet{
-		UserID:  userID,
-		Balance: 0,
+		UserID:   userID,
+		Type:     walletType,
+		Currency: currency,
+		Balance:  model.Money{},
+		Status:   model.WalletActive,
+		Version:  1,
 	}
 
 	return wallet, g.db.WithContext(ctx).Create(wallet).Error
 }
 
-func (g *GormRepo) Deposit(ctx context.Context, userID, walletID int64, funds float64) (*model.Wallet, error) {
-	tx := g.db.WithContext(ctx).Begin()
-	defer tx.Commit()
+// ListWallets returns all wallets of a user in creation order.
+func (g *GormRepo) ListWallets(ctx context.Context, userID int64) (_ []model.Wallet, err error) {
+	defer func() { err = dbError(err) }()
 
-	wallet := &model.Wallet{}
-	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("id=?", walletID).
-		Where("user_id=?", userID).
-		First(wallet)
-	if result.Error != nil {
-		return wallet, result.Error
+	var wallets []model.Wallet
+	err = g.read(ctx, func(db *gorm.DB, _ int64) error {
+		wallets = []model.Wallet{}
+		return db.Where("user_id=?", userID).
+			Order("id").
+			Find(&wallets).Error
+	})
+
+	return wallets, err
+}
+
+func (g *GormRepo) Deposit(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	guard model.WalletGuard,
+) (*model.Wallet, error) {
+	return g.changeWallet(ctx, userID, walletID, func(tx *gorm.DB, wallet *model.Wallet) error {
+		if err := checkWallet(wallet, guard, false); err != nil {
+			return err
+		}
+
+		if err := checkCurrency(wallet, funds, currency); err != nil {
+			return err
+		}
+
+		wallet.Balance = wallet.Balance.Add(funds)
+		if err := saveWallet(tx, wallet); err != nil {
+			return err
+		}
+
+		return record(tx, wallet, model.LedgerEntry{
+			Action: model.ActionDeposit,
+			Amount: funds,
+		})
+	})
+}
+
+// DepositBatch applies deposits to a wallet in a single transaction, in
+// order. Each deposit succeeds or fails on its own, the wallet of a
+// successful one is the wallet after it with its ledger entry.
+func (g *GormRepo) DepositBatch(
+	ctx context.Context,
+	userID, walletID int64,
+	deposits []Deposit,
+) ([]DepositResult, error) {
+	var results []DepositResult
+	_, err := g.changeWallet(ctx, userID, walletID, func(tx *gorm.DB, wallet *model.Wallet) error {
+		results = make([]DepositResult, len(deposits))
+		read := wallet.Version
+		for i, deposit := range deposits {
+			if results[i].Err = checkWallet(wallet, deposit.Guard, false); results[i].Err != nil {
+				continue
+			}
+			if results[i].Err = checkCurrency(wallet, deposit.Funds, deposit.Currency); results[i].Err != nil {
+				continue
+			}
+
+			// every deposit is a version of its own, so the guards of the
+			// later ones see it
+			wallet.Balance = wallet.Balance.Add(deposit.Funds)
+			wallet.Version++
+			wallet.Entries = nil
+			err := record(tx, wallet, model.LedgerEntry{
+				Action: model.ActionDeposit,
+				Amount: deposit.Funds,
+			})
+			if err != nil {
+				return err
+			}
+
+			after := *wallet
+			results[i].Wallet = &after
+		}
+
+		if wallet.Version == read {
+			return nil
+		}
+
+		// the wallet is saved once for the whole batch
+		return updateWallet(tx, wallet, read)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (g *GormRepo) Withdraw(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	guard model.WalletGuard,
+) (*model.Wallet, error) {
+	return g.changeWallet(ctx, userID, walletID, func(tx *gorm.DB, wallet *model.Wallet) error {
+		if err := checkWallet(wallet, guard, true); err != nil {
+			return err
+		}
+
+		if err := checkCurrency(wallet, funds, currency); err != nil {
+			return err
+		}
+
+		if err := releaseExpiredHolds(tx, wallet); err != nil {
+			return err
+		}
+
+		// held funds cannot be withdrawn
+		if wallet.Available().LessThan(funds) {
+			return walletuc.ErrInsufficientFunds
+		}
+
+		wallet.Balance = wallet.Balance.Sub(funds)
+		if err := saveWallet(tx, wallet); err != nil {
+			return err
+		}
+
+		return record(tx, wallet, model.LedgerEntry{
+			Action: model.ActionWithdraw,
+			Amount: funds,
+		})
+	})
+}
+
+// Transfer moves funds between two wallets within a single transaction.
+// Amount is taken from the source wallet in Currency and ToAmount is given
+// to the target wallet in ToCurrency, the quote of a conversion is used up.
+func (g *GormRepo) Transfer(
+	ctx context.Context,
+	transfer *model.Transfer,
+	guard model.WalletGuard,
+) (_ *model.Transfer, err error) {
+	defer func() { err = dbError(err) }()
+
+	fromWalletID, toWalletID := transfer.FromWalletID, transfer.ToWalletID
+	if fromWalletID == toWalletID {
+		return nil, walletuc.ErrSameWallet
+	}
+
+	// lock the wallets in id order so opposite transfers cannot deadlock
+	lockOrder := []int64{fromWalletID, toWalletID}
+	if toWalletID < fromWalletID {
+		lockOrder = []int64{toWalletID, fromWalletID}
+	}
+
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		// a retried transfer is created again
+		transfer.ID, transfer.FromWallet, transfer.ToWallet = 0, nil, nil
+
+		wallets := map[int64]*model.Wallet{}
+		for _, walletID := range lockOrder {
+			wallet := &model.Wallet{}
+			result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id=?", walletID).
+				First(wallet)
+			if result.Error != nil {
+				return notFound(result.Error, walletuc.ErrWalletNotFound)
+			}
+			wallets[walletID] = wallet
+		}
+
+		from, to := wallets[fromWalletID], wallets[toWalletID]
+		if err := checkWallet(from, guard, true); err != nil {
+			return err
+		}
+		if err := checkWallet(to, guard, false); err != nil {
+			return err
+		}
+
+		if err := checkCurrency(from, transfer.Amount, transfer.Currency); err != nil {
+			return err
+		}
+		if err := checkCurrency(to, transfer.ToAmount, transfer.ToCurrency); err != nil {
+			return err
+		}
+
+		if err := releaseExpiredHolds(tx, from); err != nil {
+			return err
+		}
+
+		if from.Available().LessThan(transfer.Amount) {
+			return walletuc.ErrInsufficientFunds
+		}
+
+		if transfer.QuoteID != nil {
+			if err := useQuote(tx, *transfer.QuoteID); err != nil {
+				return err
+			}
+		}
+
+		return moveFunds(tx, transfer, from, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// Close marks a wallet as closed, the row and its ledger are kept.
+// A remaining balance is swept to sweepWalletID, which must be an open
+// wallet of the same user and currency.
+func (g *GormRepo) Close(
+	ctx context.Context,
+	userID, walletID int64,
+	reason string,
+	sweepWalletID *int64,
+	guard model.WalletGuard,
+) (_ *model.Wallet, err error) {
+	defer func() { err = dbError(err) }()
+
+	if sweepWalletID != nil && *sweepWalletID == walletID {
+		return nil, walletuc.ErrSweepWallet
+	}
+
+	// lock the wallets in id order so a concurrent sweep cannot deadlock
+	lockOrder := []int64{walletID}
+	if sweepWalletID != nil {
+		lockOrder = append(lockOrder, *sweepWalletID)
+		if *sweepWalletID < walletID {
+			lockOrder = []int64{*sweepWalletID, walletID}
+		}
+	}
+
+	var wallet *model.Wallet
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		wallets := map[int64]*model.Wallet{}
+		for _, id := range lockOrder {
+			locked, err := lockWallet(tx, userID, id)
+			if errors.Is(err, walletuc.ErrWalletNotFound) && id != walletID {
+				return walletuc.ErrSweepWallet
+			}
+			if err != nil {
+				return err
+			}
+			wallets[id] = locked
+		}
+
+		wallet = wallets[walletID]
+		if err := checkWallet(wallet, guard, true); err != nil {
+			return err
+		}
+
+		if err := releaseExpiredHolds(tx, wallet); err != nil {
+			return err
+		}
+
+		if !wallet.Held.IsZero() {
+			return walletuc.ErrWalletHeld
+		}
+
+		if !wallet.Balance.IsZero() {
+			if sweepWalletID == nil {
+				return walletuc.ErrWalletNotEmpty
+			}
+
+			sweep := wallets[*sweepWalletID]
+			if sweep.Closed() || sweep.Currency != wallet.Currency {
+				return walletuc.ErrSweepWallet
+			}
+			if err := guard(sweep, false); err != nil {
+				return err
+			}
+
+			err := moveFunds(tx, &model.Transfer{
+				FromWalletID: wallet.ID,
+				ToWalletID:   sweep.ID,
+				Amount:       wallet.Balance,
+				Currency:     wallet.Currency,
+				ToAmount:     wallet.Balance,
+				ToCurrency:   sweep.Currency,
+				Rate:         model.OneRate(),
+			}, wallet, sweep)
+			if err != nil {
+				return err
+			}
+
+			// the sweep is part of the change of the closed wallet
+			wallet.Entries = append(wallet.Entries, sweep.Entries...)
+		}
+
+		closedAt := time.Now()
+		wallet.ClosedAt = &closedAt
+		wallet.CloseReason = reason
+
+		return setStatus(tx, wallet, model.WalletClosed, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// SetStatus changes the status of a wallet once guard accepts it.
+func (g *GormRepo) SetStatus(
+	ctx context.Context,
+	walletID int64,
+	status model.WalletStatus,
+	reason string,
+	guard model.WalletGuard,
+) (_ *model.Wallet, err error) {
+	defer func() { err = dbError(err) }()
+
+	var wallet *model.Wallet
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		wallet = &model.Wallet{}
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id=?", walletID).
+			First(wallet)
+		if result.Error != nil {
+			return notFound(result.Error, walletuc.ErrWalletNotFound)
+		}
+
+		if wallet.Closed() {
+			return walletuc.ErrWalletClosed
+		}
+
+		if err := guard(wallet, false); err != nil {
+			return err
+		}
+
+		return setStatus(tx, wallet, status, reason)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	wallet.Balance += funds
-	return wallet, tx.Save(wallet).Error
+	return wallet, nil
+}
+
+// ListStatusChanges returns the status history of a wallet, oldest first.
+func (g *GormRepo) ListStatusChanges(ctx context.Context, walletID int64) (_ []model.WalletStatusChange, err error) {
+	defer func() { err = dbError(err) }()
+
+	var changes []model.WalletStatusChange
+	err = g.read(ctx, func(db *gorm.DB, minVersion int64) error {
+		wallet := &model.Wallet{}
+		if err := db.Where("id=?", walletID).First(wallet).Error; err != nil {
+			return notFound(err, walletuc.ErrWalletNotFound)
+		}
+		if wallet.Version < minVersion {
+			return errStaleRead
+		}
+
+		changes = []model.WalletStatusChange{}
+		return db.Where("wallet_id=?", walletID).
+			Order("id").
+			Find(&changes).Error
+	})
+
+	return changes, err
 }
 
-func (g *GormRepo) Withdraw(ctx context.Context, userID, walletID int64, funds float64) (*model.Wallet, error) {
-	tx := g.db.WithContext(ctx).Begin()
-	defer tx.Commit()
+// GetWallet reads a wallet without locking it, from the replica when it is
+// fresh enough and has the version the request must see.
+func (g *GormRepo) GetWallet(ctx context.Context, userID, walletID int64) (_ *model.Wallet, err error) {
+	defer func() { err = dbError(err) }()
+
+	var wallet *model.Wallet
+	err = g.read(ctx, func(db *gorm.DB, minVersion int64) error {
+		var err error
+		wallet, err = readWallet(db, userID, walletID)
+		if err == nil && wallet.Version < minVersion {
+			return errStaleRead
+		}
 
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if wallet.Closed() {
+		return nil, walletuc.ErrWalletClosed
+	}
+
+	return wallet, nil
+}
+
+// ListTransactions reads the ledger of a wallet like GetWallet reads the wallet.
+func (g *GormRepo) ListTransactions(
+	ctx context.Context,
+	userID, walletID int64,
+	filter model.LedgerFilter,
+) (_ []model.LedgerEntry, err error) {
+	defer func() { err = dbError(err) }()
+
+	var entries []model.LedgerEntry
+	err = g.read(ctx, func(db *gorm.DB, minVersion int64) error {
+		wallet, err := readWallet(db, userID, walletID)
+		if err != nil {
+			return err
+		}
+		if wallet.Version < minVersion {
+			return errStaleRead
+		}
+
+		query := db.Where("wallet_id=?", walletID)
+		if filter.Action != "" {
+			query = query.Where("action=?", filter.Action)
+		}
+		if filter.MinAmount != nil {
+			query = query.Where("amount>=?", *filter.MinAmount)
+		}
+		if filter.MaxAmount != nil {
+			query = query.Where("amount<=?", *filter.MaxAmount)
+		}
+		if filter.From != nil {
+			query = query.Where("created_at>=?", *filter.From)
+		}
+		if filter.To != nil {
+			query = query.Where("created_at<?", *filter.To)
+		}
+		if filter.BeforeID > 0 {
+			query = query.Where("id<?", filter.BeforeID)
+		}
+		if filter.Limit > 0 {
+			query = query.Limit(filter.Limit)
+		}
+
+		order := "id DESC"
+		if filter.AfterID > 0 {
+			query = query.Where("id>?", filter.AfterID)
+			order = "id"
+		}
+
+		entries = []model.LedgerEntry{}
+		return query.Order(order).Find(&entries).Error
+	})
+
+	return entries, err
+}
+
+// read runs query on the replica when the request allows its lag and the
+// query succeeds there, on the primary otherwise. A replica query sees the
+// minimum wallet version of the request, a primary one sees none.
+func (g *GormRepo) read(ctx context.Context, query func(db *gorm.DB, minVersion int64) error) error {
+	if g.replica != nil {
+		consistency := g.replica.consistency(ctx)
+		if g.replica.fresh(ctx, consistency.MaxStaleness) &&
+			query(g.replica.db.WithContext(ctx), consistency.MinVersion) == nil {
+			return nil
+		}
+	}
+
+	return query(g.db.WithContext(ctx), 0)
+}
+
+// changeWallet runs change on a wallet of the user within a transaction.
+// The wallet is locked first, unless the repo is optimistic: change is then
+// retried on a fresh read of the wallet when it conflicts with another one.
+func (g *GormRepo) changeWallet(
+	ctx context.Context,
+	userID, walletID int64,
+	change func(tx *gorm.DB, wallet *model.Wallet) error,
+) (*model.Wallet, error) {
+	for attempt := 0; ; attempt++ {
+		wallet, err := g.changeWalletOnce(ctx, userID, walletID, change)
+		if !g.optimistic || attempt >= g.retries || !errors.Is(err, walletuc.ErrConcurrentUpdate) {
+			return wallet, err
+		}
+	}
+}
+
+func (g *GormRepo) changeWalletOnce(
+	ctx context.Context,
+	userID, walletID int64,
+	change func(tx *gorm.DB, wallet *model.Wallet) error,
+) (_ *model.Wallet, err error) {
+	defer func() { err = dbError(err) }()
+
+	var wallet *model.Wallet
+	err = g.uow.Run(ctx, func(tx *gorm.DB) error {
+		var err error
+		if g.optimistic {
+			wallet, err = readWallet(tx, userID, walletID)
+		} else {
+			wallet, err = lockWallet(tx, userID, walletID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return change(tx, wallet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// readWallet reads a wallet of the user within tx without locking it.
+func readWallet(tx *gorm.DB, userID, walletID int64) (*model.Wallet, error) {
 	wallet := &model.Wallet{}
-	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("id=?", walletID).
+	result := tx.Where("id=?", walletID).
 		Where("user_id=?", userID).
 		First(wallet)
 	if result.Error != nil {
-		return wallet, result.Error
+		return nil, notFound(result.Error, walletuc.ErrWalletNotFound)
 	}
 
-	if wallet.Balance < funds {
-		return nil, errors.New(ErrWalletBalance)
+	return wallet, nil
+}
+
+// saveWallet saves a change of wallet within tx as its next version. It
+// fails with ErrConcurrentUpdate when another change was saved since the
+// wallet was read, which cannot happen to a locked wallet.
+func saveWallet(tx *gorm.DB, wallet *model.Wallet) error {
+	read := wallet.Version
+	wallet.Version++
+
+	if err := updateWallet(tx, wallet, read); err != nil {
+		wallet.Version = read
+		return err
 	}
 
-	wallet.Balance -= funds
-	return wallet, tx.Save(wallet).Error
+	return nil
 }
 
-func (g *GormRepo) GetWallet(ctx context.Context, userID, walletID int64) (*model.Wallet, error) {
-	tx := g.db.WithContext(ctx).Begin()
-	defer tx.Commit()
+// updateWallet saves wallet within tx unless it changed since it was read
+// at version read, in which case it fails with ErrConcurrentUpdate.
+func updateWallet(tx *gorm.DB, wallet *model.Wallet, read int64) error {
+	result := tx.Model(wallet).
+		Where("version=?", read).
+		Select("*").
+		Updates(wallet)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return walletuc.ErrConcurrentUpdate
+	}
+
+	return result.Error
+}
 
-	wallet := &model.Wallet{}
-	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("id=?", walletID).
-		Where("user_id=?", userID).
-		First(wallet)
+// checkCurrency ensures funds are in the currency of the wallet
+// and have no more decimal places than its minor unit.
+func checkCurrency(wallet *model.Wallet, funds model.Money, currency model.Currency) error {
+	if wallet.Currency != currency {
+		return walletuc.ErrCurrencyMismatch
+	}
+
+	if !funds.HasScale(currency.Exponent()) {
+		return walletuc.ErrFundScale
+	}
+
+	return nil
+}
 
-	return wallet, result.Error
+// checkWallet rejects changes of closed wallets and those refused by guard.
+func checkWallet(wallet *model.Wallet, guard model.WalletGuard, debit bool) error {
+	if wallet.Closed() {
+		return walletuc.ErrWalletClosed
+	}
+
+	return guard(wallet, debit)
+}
+
+// setStatus saves the new status of wallet within tx and records the change,
+// the change is added to the status changes of wallet.
+func setStatus(tx *gorm.DB, wallet *model.Wallet, status model.WalletStatus, reason string) error {
+	change := &model.WalletStatusChange{
+		WalletID:   wallet.ID,
+		FromStatus: wallet.Status,
+		ToStatus:   status,
+		Reason:     reason,
+	}
+
+	wallet.Status = status
+	wallet.StatusReason = reason
+	if err := saveWallet(tx, wallet); err != nil {
+		return err
+	}
+
+	if err := tx.Create(change).Error; err != nil {
+		return err
+	}
+
+	if err := addToOutbox(tx, wallet.ID, model.OutboxStatusChanged, change); err != nil {
+		return err
+	}
+
+	wallet.StatusChanges = append(wallet.StatusChanges, *change)
+	return nil
+}
+
+// moveFunds applies a transfer between two locked wallets within tx
+// and records both legs in the ledger.
+func moveFunds(tx *gorm.DB, transfer *model.Transfer, from, to *model.Wallet) error {
+	from.Balance = from.Balance.Sub(transfer.Amount)
+	to.Balance = to.Balance.Add(transfer.ToAmount)
+	for _, wallet := range []*model.Wallet{from, to} {
+		if err := saveWallet(tx, wallet); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Create(transfer).Error; err != nil {
+		return err
+	}
+	transfer.FromWallet, transfer.ToWallet = from, to
+
+	// the applied rate is only recorded on the legs of a conversion
+	var rate *model.Rate
+	if transfer.Currency != transfer.ToCurrency {
+		rate = &transfer.Rate
+	}
+
+	err := record(tx, from, model.LedgerEntry{
+		Action:     model.ActionTransferOut,
+		Amount:     transfer.Amount,
+		TransferID: &transfer.ID,
+		Rate:       rate,
+	})
+	if err != nil {
+		return err
+	}
+
+	return record(tx, to, model.LedgerEntry{
+		Action:     model.ActionTransferIn,
+		Amount:     transfer.ToAmount,
+		TransferID: &transfer.ID,
+		Rate:       rate,
+	})
+}
+
+// record appends a ledger entry for a balance change of wallet made within tx,
+// the entry is added to the entries of wallet.
+func record(tx *gorm.DB, wallet *model.Wallet, entry model.LedgerEntry) error {
+	entry.WalletID = wallet.ID
+	entry.BalanceAfter = wallet.Balance
+
+	if err := tx.Create(&entry).Error; err != nil {
+		return err
+	}
+
+	if err := addToOutbox(tx, wallet.ID, model.OutboxBalanceChanged, model.NewBalanceEvent(entry)); err != nil {
+		return err
+	}
+
+	wallet.Entries = append(wallet.Entries, entry)
+	return nil
+}
+
+// addToOutbox saves an event of a change made within tx, the outbox relay
+// publishes it once tx is committed.
+func addToOutbox(tx *gorm.DB, walletID int64, eventType model.OutboxEventType, payload interface{}) error {
+	event, err := model.NewOutboxEvent(walletID, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&event).Error
 }
 
 func NewRepo(db *gorm.DB) *GormRepo {
 	return &GormRepo{
-		db: db,
+		db:  db,
+		uow: NewUnitOfWork(db, 0, 0),
+	}
+}
+
+// WithReplica reads the wallets and their history from a read replica
+// lagging at most maxStaleness behind the primary, unless the request
+// bounds the staleness itself. The primary is read when the replica fails.
+func (g *GormRepo) WithReplica(db *gorm.DB, maxStaleness time.Duration) *GormRepo {
+	g.replica = &replica{
+		db:           db,
+		maxStaleness: maxStaleness,
+	}
+
+	return g
+}
+
+// WithTxRetries runs a transaction failing on a serialization failure or a
+// deadlock again up to retries times, after a backoff of up to backoff
+// doubling with every retry.
+func (g *GormRepo) WithTxRetries(retries int, backoff time.Duration) *GormRepo {
+	g.uow = NewUnitOfWork(g.db, retries, backoff)
+
+	return g
+}
+
+// NewOptimisticRepo returns a repo which changes the balance of a wallet
+// without locking it, a conflicting change is retried up to retries times.
+// Transfers, holds and status changes still lock their wallets.
+func NewOptimisticRepo(db *gorm.DB, retries int) *GormRepo {
+	return &GormRepo{
+		db:         db,
+		uow:        NewUnitOfWork(db, 0, 0),
+		optimistic: true,
+		retries:    retries,
 	}
 }
@@ -0,0 +1,246 @@
+package pkg
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
+)
+
+// TaskQueue runs the tasks of each name on a pool of its own, so a burst
+// of one task does not hold up the others. Jobs are kept in memory, they
+// can only be looked up on the instance they were submitted to.
+type TaskQueue struct {
+	cfg Tasks
+
+	mu      sync.Mutex
+	pools   map[string]chan *queuedTask
+	jobs    map[string]*model.Job
+	closed  bool
+	workers sync.WaitGroup
+}
+
+// queuedTask is a task waiting for a worker. Tasks run for a caller carry
+// its context, jobs get theirs when they start.
+type queuedTask struct {
+	ctx     context.Context
+	fn      walletuc.TaskFunc
+	timeout time.Duration
+	job     *model.Job
+
+	result interface{}
+	err    error
+	done   chan struct{}
+}
+
+// Run executes fn on the pool of task and waits at most the timeout of
+// the task for its result.
+func (q *TaskQueue) Run(ctx context.Context, task string, fn walletuc.TaskFunc) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, q.cfg.timeout(task))
+	defer cancel()
+
+	queued := &queuedTask{ctx: ctx, fn: fn, done: make(chan struct{})}
+
+	q.mu.Lock()
+	err := q.enqueue(task, queued)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-queued.done:
+		return queued.result, queued.err
+	case <-ctx.Done():
+		return nil, taskError(ctx)
+	}
+}
+
+// Submit queues fn as a job on the pool of task, the timeout of the task
+// starts when the job does.
+func (q *TaskQueue) Submit(task string, fn walletuc.TaskFunc) (*model.Job, error) {
+	id, err := jobID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.Job{
+		ID:        id,
+		Task:      task,
+		Status:    model.JobQueued,
+		CreatedAt: time.Now(),
+	}
+	queued := &queuedTask{
+		ctx:     context.Background(),
+		fn:      fn,
+		timeout: q.cfg.timeout(task),
+		job:     job,
+		done:    make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.forget(job.CreatedAt.Add(-q.cfg.JobRetention))
+	if err = q.enqueue(task, queued); err != nil {
+		return nil, err
+	}
+	q.jobs[job.ID] = job
+
+	submitted := *job
+	return &submitted, nil
+}
+
+// Job returns a copy of a job, jobs are forgotten once they have been
+// finished for the job retention.
+func (q *TaskQueue) Job(id string) (*model.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok || (job.Finished() && job.FinishedAt.Before(time.Now().Add(-q.cfg.JobRetention))) {
+		return nil, walletuc.ErrJobNotFound
+	}
+
+	found := *job
+	return &found, nil
+}
+
+// Close rejects new tasks and waits for the queued ones to finish.
+func (q *TaskQueue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		for _, pool := range q.pools {
+			close(pool)
+		}
+	}
+	q.mu.Unlock()
+
+	q.workers.Wait()
+}
+
+// enqueue adds queued to the pool of task, the caller holds the lock.
+func (q *TaskQueue) enqueue(task string, queued *queuedTask) error {
+	if q.closed {
+		return walletuc.ErrShuttingDown
+	}
+
+	pool, ok := q.pools[task]
+	if !ok {
+		pool = q.start(task)
+	}
+
+	select {
+	case pool <- queued:
+		return nil
+	default:
+		return walletuc.ErrQueueFull
+	}
+}
+
+// start creates the pool of task, the caller holds the lock.
+func (q *TaskQueue) start(task string) chan *queuedTask {
+	pool := make(chan *queuedTask, q.cfg.queueDepth(task))
+	q.pools[task] = pool
+
+	for i := 0; i < q.cfg.workers(task); i++ {
+		q.workers.Add(1)
+		go func() {
+			defer q.workers.Done()
+
+			for queued := range pool {
+				q.execute(queued)
+			}
+		}()
+	}
+
+	return pool
+}
+
+func (q *TaskQueue) execute(queued *queuedTask) {
+	defer close(queued.done)
+
+	ctx := queued.ctx
+	if queued.job != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, queued.timeout)
+		defer cancel()
+
+		q.update(queued.job, func(job *model.Job) {
+			now := time.Now()
+			job.Status = model.JobRunning
+			job.StartedAt = &now
+		})
+	}
+
+	// the caller has given up while the task was queued
+	if ctx.Err() != nil {
+		queued.err = taskError(ctx)
+	} else {
+		queued.result, queued.err = queued.fn(ctx)
+		if queued.err != nil && ctx.Err() != nil {
+			queued.err = taskError(ctx)
+		}
+	}
+
+	if queued.job != nil {
+		q.update(queued.job, func(job *model.Job) {
+			now := time.Now()
+			job.FinishedAt = &now
+			job.Status = model.JobSucceeded
+			job.Result = queued.result
+			if queued.err != nil {
+				job.Status = model.JobFailed
+				job.Err = queued.err
+			}
+		})
+	}
+}
+
+func (q *TaskQueue) update(job *model.Job, fn func(job *model.Job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	fn(job)
+}
+
+// forget drops the jobs finished before, the caller holds the lock.
+func (q *TaskQueue) forget(before time.Time) {
+	for id, job := range q.jobs {
+		if job.Finished() && job.FinishedAt.Before(before) {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+// taskError is the error of a task whose context is done.
+func taskError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return walletuc.ErrTaskTimeout
+	}
+
+	return ctx.Err()
+}
+
+func jobID() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
+
+func NewTaskQueue(cfg Tasks) *TaskQueue {
+	return &TaskQueue{
+		cfg:   cfg,
+		pools: map[string]chan *queuedTask{},
+		jobs:  map[string]*model.Job{},
+	}
+}
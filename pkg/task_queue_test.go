@@ -0,0 +1,202 @@
+package pkg_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	. "github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ = Describe("TaskQueue", func() {
+	var (
+		ctx   context.Context
+		cfg   Tasks
+		queue *TaskQueue
+	)
+
+	// blocked is a task which runs until release is closed.
+	blocked := func(release chan struct{}) wallet.TaskFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			select {
+			case <-release:
+				return "released", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	// wait polls the job until it is finished.
+	wait := func(id string) *model.Job {
+		for i := 0; i < 100; i++ {
+			job, err := queue.Job(id)
+			assert.NoError(GinkgoT(), err)
+			if job.Finished() {
+				return job
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		assert.Fail(GinkgoT(), "job not finished")
+		return nil
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cfg = Tasks{
+			Workers:      1,
+			QueueDepth:   1,
+			Timeout:      time.Second,
+			JobRetention: time.Minute,
+		}
+	})
+
+	JustBeforeEach(func() {
+		queue = NewTaskQueue(cfg)
+	})
+
+	AfterEach(func() {
+		queue.Close()
+	})
+
+	It("returns the result of a task", func() {
+		result, err := queue.Run(ctx, "task", func(ctx context.Context) (interface{}, error) {
+			return 42, nil
+		})
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 42, result)
+	})
+
+	It("rejects tasks over the queue depth", func() {
+		release := make(chan struct{})
+		defer close(release)
+
+		// one task runs, one waits and the next is rejected
+		_, err := queue.Submit("task", blocked(release))
+		assert.NoError(GinkgoT(), err)
+		time.Sleep(20 * time.Millisecond)
+		_, err = queue.Submit("task", blocked(release))
+		assert.NoError(GinkgoT(), err)
+
+		_, err = queue.Submit("task", blocked(release))
+		assert.Equal(GinkgoT(), wallet.ErrQueueFull, err)
+
+		_, err = queue.Run(ctx, "task", blocked(release))
+		assert.Equal(GinkgoT(), wallet.ErrQueueFull, err)
+
+		// the tasks of another name have their own pool
+		_, err = queue.Run(ctx, "other", func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		})
+		assert.NoError(GinkgoT(), err)
+	})
+
+	Context("with a short timeout", func() {
+		BeforeEach(func() {
+			cfg.Timeout = 20 * time.Millisecond
+		})
+
+		It("times out a task", func() {
+			_, err := queue.Run(ctx, "task", blocked(make(chan struct{})))
+			assert.Equal(GinkgoT(), wallet.ErrTaskTimeout, err)
+		})
+
+		It("fails a job which times out", func() {
+			job, err := queue.Submit("task", blocked(make(chan struct{})))
+			assert.NoError(GinkgoT(), err)
+
+			job = wait(job.ID)
+			assert.Equal(GinkgoT(), model.JobFailed, job.Status)
+			assert.Equal(GinkgoT(), wallet.ErrTaskTimeout, job.Err)
+		})
+	})
+
+	Context("with limits of a task", func() {
+		BeforeEach(func() {
+			cfg.TaskTimeout = map[string]time.Duration{"slow": 20 * time.Millisecond}
+			cfg.TaskWorkers = map[string]int{"wide": 2}
+			cfg.TaskQueueDepth = map[string]int{"wide": 2}
+		})
+
+		It("applies them to the task only", func() {
+			_, err := queue.Run(ctx, "slow", blocked(make(chan struct{})))
+			assert.Equal(GinkgoT(), wallet.ErrTaskTimeout, err)
+
+			// both jobs run at once on the two workers of the task
+			wide := make(chan struct{})
+			defer close(wide)
+			first, err := queue.Submit("wide", blocked(wide))
+			assert.NoError(GinkgoT(), err)
+			second, err := queue.Submit("wide", blocked(wide))
+			assert.NoError(GinkgoT(), err)
+			time.Sleep(20 * time.Millisecond)
+
+			for _, id := range []string{first.ID, second.ID} {
+				job, err := queue.Job(id)
+				assert.NoError(GinkgoT(), err)
+				assert.Equal(GinkgoT(), model.JobRunning, job.Status)
+			}
+
+			release := make(chan struct{})
+			close(release)
+			result, err := queue.Run(ctx, "task", blocked(release))
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "released", result)
+		})
+	})
+
+	It("runs a job to its outcome", func() {
+		release := make(chan struct{})
+		job, err := queue.Submit("task", blocked(release))
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), "task", job.Task)
+		assert.Equal(GinkgoT(), model.JobQueued, job.Status)
+
+		close(release)
+		job = wait(job.ID)
+		assert.Equal(GinkgoT(), model.JobSucceeded, job.Status)
+		assert.Equal(GinkgoT(), "released", job.Result)
+		assert.NotNil(GinkgoT(), job.StartedAt)
+		assert.NotNil(GinkgoT(), job.FinishedAt)
+
+		failure := errors.New("failure")
+		job, err = queue.Submit("task", func(ctx context.Context) (interface{}, error) {
+			return nil, failure
+		})
+		assert.NoError(GinkgoT(), err)
+
+		job = wait(job.ID)
+		assert.Equal(GinkgoT(), model.JobFailed, job.Status)
+		assert.Equal(GinkgoT(), failure, job.Err)
+	})
+
+	It("does not find unknown jobs", func() {
+		_, err := queue.Job("unknown")
+		assert.Equal(GinkgoT(), wallet.ErrJobNotFound, err)
+	})
+
+	It("finishes the queued tasks and rejects new ones once closed", func() {
+		job, err := queue.Submit("task", func(ctx context.Context) (interface{}, error) {
+			time.Sleep(20 * time.Millisecond)
+			return nil, nil
+		})
+		assert.NoError(GinkgoT(), err)
+
+		queue.Close()
+
+		job, err = queue.Job(job.ID)
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), model.JobSucceeded, job.Status)
+
+		_, err = queue.Run(ctx, "task", func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		})
+		assert.Equal(GinkgoT(), wallet.ErrShuttingDown, err)
+	})
+})
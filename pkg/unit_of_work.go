@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+// retryableCodes are the Postgres error codes of transactions which may
+// succeed when they are run again.
+var retryableCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// UnitOfWork runs work within a database transaction. The transaction is
+// committed when work succeeds and rolled back when it fails or panics, a
+// transaction failing on a serialization failure or a deadlock is run again
+// up to retries times after a jittered backoff.
+type UnitOfWork struct {
+	db      *gorm.DB
+	retries int
+	backoff time.Duration
+}
+
+// Run runs work within a transaction, work must be safe to run again. The
+// error is the one of work, or of the database when it cannot begin or
+// commit the transaction.
+func (u *UnitOfWork) Run(ctx context.Context, work func(tx *gorm.DB) error) error {
+	for attempt := 0; ; attempt++ {
+		err := u.runOnce(ctx, work)
+		if err == nil || attempt >= u.retries || !retryable(err) {
+			return err
+		}
+
+		if waitErr := u.wait(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
+
+func (u *UnitOfWork) runOnce(ctx context.Context, work func(tx *gorm.DB) error) (err error) {
+	tx := u.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err = work(tx); err != nil {
+		return err
+	}
+
+	committed = true
+	return tx.Commit().Error
+}
+
+// wait sleeps before the retry of attempt, up to twice as long as before
+// the previous one. It returns early with the error of a done ctx.
+func (u *UnitOfWork) wait(ctx context.Context, attempt int) error {
+	if u.backoff <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(u.backoff << attempt))))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryable tells whether err fails a transaction which may succeed when
+// it is run again.
+func retryable(err error) bool {
+	pgErr := &pgconn.PgError{}
+	return errors.As(err, &pgErr) && retryableCodes[pgErr.Code]
+}
+
+// NewUnitOfWork returns a unit of work on db retrying a transaction up to
+// retries times, the backoff before the first retry is up to backoff.
+func NewUnitOfWork(db *gorm.DB, retries int, backoff time.Duration) *UnitOfWork {
+	return &UnitOfWork{
+		db:      db,
+		retries: retries,
+		backoff: backoff,
+	}
+}
@@ -0,0 +1,228 @@
+package pkg_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/sysdevguru/bluelabs/pkg"
+	"github.com/sysdevguru/bluelabs/usecase/wallet"
+
+	"github.com/jackc/pgconn"
+	. "github.com/onsi/ginkgo"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// txConnector connects to a database which only has transactions, it
+// counts them and fails them with the injected errors.
+type txConnector struct {
+	mu         sync.Mutex
+	beginErrs  []error
+	commitErrs []error
+	begins     int
+	commits    int
+	rollbacks  int
+}
+
+func (c *txConnector) Connect(context.Context) (driver.Conn, error) {
+	return &txConn{c}, nil
+}
+
+func (c *txConnector) Driver() driver.Driver {
+	return nil
+}
+
+// next pops the next injected error of errs.
+func (c *txConnector) next(errs *[]error) error {
+	if len(*errs) == 0 {
+		return nil
+	}
+
+	err := (*errs)[0]
+	*errs = (*errs)[1:]
+	return err
+}
+
+type txConn struct {
+	c *txConnector
+}
+
+func (t *txConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statements are not supported")
+}
+
+func (t *txConn) Close() error {
+	return nil
+}
+
+func (t *txConn) Begin() (driver.Tx, error) {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	t.c.begins++
+	if err := t.c.next(&t.c.beginErrs); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *txConn) Commit() error {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	t.c.commits++
+	return t.c.next(&t.c.commitErrs)
+}
+
+func (t *txConn) Rollback() error {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	t.c.rollbacks++
+	return nil
+}
+
+var _ = Describe("UnitOfWork", func() {
+	var (
+		ctx       context.Context
+		connector *txConnector
+		db        *gorm.DB
+	)
+
+	serializationFailure := &pgconn.PgError{Code: "40001"}
+	deadlock := &pgconn.PgError{Code: "40P01"}
+
+	// failing is a work failing with errs, one per attempt, and then
+	// succeeding. It counts its attempts.
+	failing := func(attempts *int, errs ...error) func(tx *gorm.DB) error {
+		return func(tx *gorm.DB) error {
+			*attempts++
+			if *attempts <= len(errs) {
+				return errs[*attempts-1]
+			}
+
+			return nil
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		connector = &txConnector{}
+
+		var err error
+		db, err = gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(connector)}), &gorm.Config{})
+		assert.NoError(GinkgoT(), err)
+	})
+
+	It("commits a work which succeeds", func() {
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts))
+
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 1, attempts)
+		assert.Equal(GinkgoT(), 1, connector.commits)
+		assert.Equal(GinkgoT(), 0, connector.rollbacks)
+	})
+
+	It("rolls back a work which fails", func() {
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts, wallet.ErrInsufficientFunds))
+
+		assert.ErrorIs(GinkgoT(), err, wallet.ErrInsufficientFunds)
+		assert.Equal(GinkgoT(), 1, attempts)
+		assert.Equal(GinkgoT(), 0, connector.commits)
+		assert.Equal(GinkgoT(), 1, connector.rollbacks)
+	})
+
+	It("rolls back a work which panics", func() {
+		uow := NewUnitOfWork(db, 3, time.Millisecond)
+
+		assert.PanicsWithValue(GinkgoT(), "failed", func() {
+			_ = uow.Run(ctx, func(tx *gorm.DB) error {
+				panic("failed")
+			})
+		})
+		assert.Equal(GinkgoT(), 0, connector.commits)
+		assert.Equal(GinkgoT(), 1, connector.rollbacks)
+	})
+
+	It("returns the error of begin", func() {
+		beginErr := errors.New("connection refused")
+		connector.beginErrs = []error{beginErr}
+
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts))
+
+		assert.ErrorIs(GinkgoT(), err, beginErr)
+		assert.Equal(GinkgoT(), 0, attempts)
+	})
+
+	It("returns the error of commit", func() {
+		commitErr := errors.New("connection reset")
+		connector.commitErrs = []error{commitErr}
+
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts))
+
+		assert.ErrorIs(GinkgoT(), err, commitErr)
+		assert.Equal(GinkgoT(), 1, attempts)
+		assert.Equal(GinkgoT(), 1, connector.commits)
+	})
+
+	It("retries serialization failures and deadlocks", func() {
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts, serializationFailure, deadlock))
+
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 3, attempts)
+		assert.Equal(GinkgoT(), 2, connector.rollbacks)
+		assert.Equal(GinkgoT(), 1, connector.commits)
+	})
+
+	It("retries a commit which fails to serialize", func() {
+		connector.commitErrs = []error{serializationFailure}
+
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts))
+
+		assert.NoError(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 2, attempts)
+		assert.Equal(GinkgoT(), 2, connector.commits)
+	})
+
+	It("gives up after the retries", func() {
+		attempts := 0
+		err := NewUnitOfWork(db, 2, time.Millisecond).Run(ctx, failing(&attempts, deadlock, deadlock, deadlock, deadlock))
+
+		assert.ErrorIs(GinkgoT(), err, deadlock)
+		assert.Equal(GinkgoT(), 3, attempts)
+		assert.Equal(GinkgoT(), 3, connector.rollbacks)
+	})
+
+	It("does not retry other errors", func() {
+		attempts := 0
+		err := NewUnitOfWork(db, 3, time.Millisecond).Run(ctx, failing(&attempts, &pgconn.PgError{Code: "23505"}))
+
+		assert.Error(GinkgoT(), err)
+		assert.Equal(GinkgoT(), 1, attempts)
+	})
+
+	It("stops retrying when the context is done", func() {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		attempts := 0
+		started := time.Now()
+		err := NewUnitOfWork(db, 3, time.Hour).Run(ctx, failing(&attempts, serializationFailure, serializationFailure))
+
+		assert.ErrorIs(GinkgoT(), err, serializationFailure)
+		assert.Equal(GinkgoT(), 1, attempts)
+		assert.Less(GinkgoT(), time.Since(started), time.Second)
+	})
+})
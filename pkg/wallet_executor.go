@@ -0,0 +1,258 @@
+package pkg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	walletuc "github.com/sysdevguru/bluelabs/usecase/wallet"
+)
+
+// Deposit is a deposit of a batch, it is refused by Guard like a single deposit.
+type Deposit struct {
+	Funds    model.Money
+	Currency model.Currency
+	Guard    model.WalletGuard
+}
+
+// DepositResult is the outcome of a deposit of a batch.
+type DepositResult struct {
+	Wallet *model.Wallet
+	Err    error
+}
+
+// BatchRepo is a wallet repository which applies a batch of deposits to a
+// wallet at once.
+type BatchRepo interface {
+	walletuc.Repo
+	DepositBatch(ctx context.Context, userID, walletID int64, deposits []Deposit) ([]DepositResult, error)
+}
+
+// WalletExecutor funnels the deposits and withdrawals of a wallet through
+// the goroutine of its shard, so a hot wallet takes one connection instead
+// of queueing on its row lock with many. Consecutive deposits to a wallet
+// are applied in a single transaction. The other changes go to the repo.
+type WalletExecutor struct {
+	BatchRepo
+
+	cfg Executor
+
+	mu      sync.RWMutex
+	shards  []chan *walletOp
+	closed  bool
+	workers sync.WaitGroup
+}
+
+// walletOp is a change waiting for the goroutine of its shard. A deposit
+// may be batched, other changes are run as they are.
+type walletOp struct {
+	ctx      context.Context
+	userID   int64
+	walletID int64
+	deposit  *Deposit
+	run      func(ctx context.Context) (*model.Wallet, error)
+
+	wallet *model.Wallet
+	err    error
+	done   chan struct{}
+}
+
+func (e *WalletExecutor) Deposit(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	guard model.WalletGuard,
+) (*model.Wallet, error) {
+	return e.execute(&walletOp{
+		ctx:      ctx,
+		userID:   userID,
+		walletID: walletID,
+		deposit: &Deposit{
+			Funds:    funds,
+			Currency: currency,
+			Guard:    guard,
+		},
+	})
+}
+
+func (e *WalletExecutor) Withdraw(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	guard model.WalletGuard,
+) (*model.Wallet, error) {
+	return e.execute(&walletOp{
+		ctx:      ctx,
+		userID:   userID,
+		walletID: walletID,
+		run: func(ctx context.Context) (*model.Wallet, error) {
+			return e.BatchRepo.Withdraw(ctx, userID, walletID, funds, currency, guard)
+		},
+	})
+}
+
+// Stop rejects new changes and waits for the queued ones.
+func (e *WalletExecutor) Stop() {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		for _, shard := range e.shards {
+			close(shard)
+		}
+	}
+	e.mu.Unlock()
+
+	e.workers.Wait()
+}
+
+// execute queues op on its shard and waits for its outcome.
+func (e *WalletExecutor) execute(op *walletOp) (*model.Wallet, error) {
+	op.done = make(chan struct{})
+
+	e.mu.RLock()
+	if e.closed {
+		e.mu.RUnlock()
+		return nil, walletuc.ErrShuttingDown
+	}
+
+	select {
+	case e.shards[op.walletID%int64(len(e.shards))] <- op:
+		e.mu.RUnlock()
+	case <-op.ctx.Done():
+		e.mu.RUnlock()
+		return nil, taskError(op.ctx)
+	}
+
+	select {
+	case <-op.done:
+		return op.wallet, op.err
+	case <-op.ctx.Done():
+		return nil, taskError(op.ctx)
+	}
+}
+
+// serve runs the changes of shard in order until it is closed.
+func (e *WalletExecutor) serve(shard chan *walletOp) {
+	defer e.workers.Done()
+
+	var next *walletOp
+	for {
+		op := next
+		next = nil
+		if op == nil {
+			var ok bool
+			if op, ok = <-shard; !ok {
+				return
+			}
+		}
+
+		if op.deposit == nil {
+			e.run(op)
+			continue
+		}
+
+		// the deposits already queued after op join its batch, the first
+		// other change ends it and runs next
+		batch := []*walletOp{op}
+	collect:
+		for len(batch) < e.cfg.MaxBatch {
+			select {
+			case queued, ok := <-shard:
+				if !ok {
+					break collect
+				}
+				if queued.deposit == nil || queued.userID != op.userID || queued.walletID != op.walletID {
+					next = queued
+					break collect
+				}
+				batch = append(batch, queued)
+			default:
+				break collect
+			}
+		}
+
+		e.depositBatch(batch)
+	}
+}
+
+func (e *WalletExecutor) run(op *walletOp) {
+	defer close(op.done)
+
+	// the caller has given up while the change was queued
+	if op.ctx.Err() != nil {
+		op.err = taskError(op.ctx)
+		return
+	}
+
+	op.wallet, op.err = op.run(op.ctx)
+}
+
+func (e *WalletExecutor) depositBatch(batch []*walletOp) {
+	pending := []*walletOp{}
+	for _, op := range batch {
+		if op.ctx.Err() != nil {
+			op.err = taskError(op.ctx)
+			close(op.done)
+			continue
+		}
+
+		pending = append(pending, op)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	deposits := make([]Deposit, len(pending))
+	for i, op := range pending {
+		deposits[i] = *op.deposit
+	}
+
+	ctx, cancel := batchContext(pending)
+	defer cancel()
+
+	results, err := e.BatchRepo.DepositBatch(ctx, pending[0].userID, pending[0].walletID, deposits)
+	for i, op := range pending {
+		if err != nil {
+			op.err = err
+		} else {
+			op.wallet, op.err = results[i].Wallet, results[i].Err
+		}
+		close(op.done)
+	}
+}
+
+// batchContext lasts until the last caller of batch gives up, a caller
+// giving up earlier does not roll back the deposits of the others.
+func batchContext(batch []*walletOp) (context.Context, context.CancelFunc) {
+	deadline := time.Time{}
+	for _, op := range batch {
+		opDeadline, ok := op.ctx.Deadline()
+		if !ok {
+			return context.WithCancel(context.Background())
+		}
+		if opDeadline.After(deadline) {
+			deadline = opDeadline
+		}
+	}
+
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+func NewWalletExecutor(repo BatchRepo, cfg Executor) *WalletExecutor {
+	executor := &WalletExecutor{
+		BatchRepo: repo,
+		cfg:       cfg,
+		shards:    make([]chan *walletOp, cfg.Shards),
+	}
+
+	for i := range executor.shards {
+		executor.shards[i] = make(chan *walletOp, cfg.QueueDepth)
+		executor.workers.Add(1)
+		go executor.serve(executor.shards[i])
+	}
+
+	return executor
+}
@@ -0,0 +1,168 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+	webhookuc "github.com/sysdevguru/bluelabs/usecase/webhook"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormWebhookRepo struct {
+	db *gorm.DB
+}
+
+func (g *GormWebhookRepo) Create(ctx context.Context, webhook *model.Webhook) error {
+	return g.db.WithContext(ctx).Create(webhook).Error
+}
+
+func (g *GormWebhookRepo) List(ctx context.Context) ([]model.Webhook, error) {
+	webhooks := []model.Webhook{}
+	err := g.db.WithContext(ctx).Order("id").Find(&webhooks).Error
+
+	return webhooks, err
+}
+
+func (g *GormWebhookRepo) Get(ctx context.Context, webhookID int64) (*model.Webhook, error) {
+	webhook := &model.Webhook{}
+	err := g.db.WithContext(ctx).First(webhook, webhookID).Error
+	if err != nil {
+		return nil, notFound(err, webhookuc.ErrWebhookNotFound)
+	}
+
+	return webhook, nil
+}
+
+// Delete removes the webhook, its deliveries are deleted by the foreign key.
+func (g *GormWebhookRepo) Delete(ctx context.Context, webhookID int64) error {
+	result := g.db.WithContext(ctx).Delete(&model.Webhook{}, webhookID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return webhookuc.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Enqueue stores the deliveries, skipping the ones of an event already
+// queued for the webhook.
+func (g *GormWebhookRepo) Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	return g.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).Error
+}
+
+func (g *GormWebhookRepo) Wallets(ctx context.Context, walletIDs []int64) ([]model.Wallet, error) {
+	wallets := []model.Wallet{}
+	err := g.db.WithContext(ctx).Where("id IN ?", walletIDs).Find(&wallets).Error
+
+	return wallets, err
+}
+
+// Claim moves the due deliveries to leaseUntil in a single statement,
+// concurrent workers skip the rows another one is claiming.
+func (g *GormWebhookRepo) Claim(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]model.WebhookDelivery, error) {
+	db := g.db.WithContext(ctx)
+
+	deliveries := []model.WebhookDelivery{}
+	err := db.Raw(`UPDATE webhook_deliveries SET next_attempt_at=? WHERE id IN (
+		SELECT id FROM webhook_deliveries
+		WHERE status=? AND next_attempt_at<=?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	) RETURNING *`, leaseUntil, model.DeliveryPending, now, limit).Scan(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	webhookIDs := []int64{}
+	for _, delivery := range deliveries {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+	}
+
+	webhooks := []model.Webhook{}
+	if err = db.Find(&webhooks, webhookIDs).Error; err != nil {
+		return nil, err
+	}
+
+	byID := map[int64]*model.Webhook{}
+	for i := range webhooks {
+		byID[webhooks[i].ID] = &webhooks[i]
+	}
+
+	// a webhook deleted since the claim takes its deliveries with it
+	claimed := deliveries[:0]
+	for _, delivery := range deliveries {
+		if delivery.Webhook = byID[delivery.WebhookID]; delivery.Webhook != nil {
+			claimed = append(claimed, delivery)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (g *GormWebhookRepo) SaveAttempt(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return g.db.WithContext(ctx).
+		Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at").
+		Updates(delivery).Error
+}
+
+func (g *GormWebhookRepo) ListDeliveries(
+	ctx context.Context,
+	webhookID int64,
+	status model.DeliveryStatus,
+) ([]model.WebhookDelivery, error) {
+	query := g.db.WithContext(ctx).Where("webhook_id=?", webhookID)
+	if status != "" {
+		query = query.Where("status=?", status)
+	}
+
+	deliveries := []model.WebhookDelivery{}
+	err := query.Order("id DESC").Limit(webhookuc.MaxDeliveries).Find(&deliveries).Error
+
+	return deliveries, err
+}
+
+// Replay makes the delivery pending again at at with no attempt made.
+func (g *GormWebhookRepo) Replay(
+	ctx context.Context,
+	webhookID, deliveryID int64,
+	at time.Time,
+) (*model.WebhookDelivery, error) {
+	delivery := &model.WebhookDelivery{}
+	result := g.db.WithContext(ctx).
+		Model(delivery).
+		Clauses(clause.Returning{}).
+		Where("id=? AND webhook_id=?", deliveryID, webhookID).
+		Updates(map[string]interface{}{
+			"status":          model.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": at,
+			"delivered_at":    nil,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, webhookuc.ErrDeliveryNotFound
+	}
+
+	return delivery, nil
+}
+
+func NewWebhookRepo(db *gorm.DB) *GormWebhookRepo {
+	return &GormWebhookRepo{
+		db: db,
+	}
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// HTTPWebhookSender posts webhook payloads over HTTP.
+type HTTPWebhookSender struct {
+	client *http.Client
+}
+
+// Send posts body to url and returns the response status, the response
+// body is discarded.
+func (s *HTTPWebhookSender) Send(
+	ctx context.Context,
+	url string,
+	header map[string]string,
+	body []byte,
+) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	for key, value := range header {
+		request.Header.Set(key, value)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	return response.StatusCode, nil
+}
+
+func NewHTTPWebhookSender() *HTTPWebhookSender {
+	return &HTTPWebhookSender{
+		// the attempts are bounded by their context, redirects are not followed
+		client: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package idempotency
+
+// Error is a domain error of the idempotency use cases, compare with errors.Is.
+type Error struct {
+	msg string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+var (
+	ErrInvalidKey = &Error{"invalid idempotency key"}
+	ErrKeyReused  = &Error{"idempotency key already used for a different request"}
+	ErrInProgress = &Error{"request with the same idempotency key is in progress"}
+	// ErrReservationLost is returned when a reservation was taken over
+	// after it timed out.
+	ErrReservationLost = &Error{"idempotency key reservation was taken over"}
+)
@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// MaxKeyLength is the maximum length of an Idempotency-Key.
+const MaxKeyLength = 255
+
+type Repo interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord, expiredBefore, staleBefore time.Time) (*model.IdempotencyRecord, error)
+	// Complete and Release change the reservation of key by holder, they
+	// fail with ErrReservationLost once another request took it over.
+	Complete(ctx context.Context, key, holder string, statusCode int, response []byte) error
+	Release(ctx context.Context, key, holder string) error
+	Purge(ctx context.Context, expiredBefore time.Time) error
+}
+
+type UseCase struct {
+	retention   time.Duration
+	lockTimeout time.Duration
+	repo        Repo
+}
+
+// Fingerprint identifies the request sent with an idempotency key.
+func Fingerprint(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin reserves key for the request identified by fingerprint.
+// It returns the reservation when the request must be processed, or the
+// completed record when the request must be replayed. A reservation which
+// did not complete within the lock timeout is taken over.
+func (uc *UseCase) Begin(
+	ctx context.Context,
+	key, fingerprint string,
+) (*model.IdempotencyRecord, error) {
+	if key == "" || len(key) > MaxKeyLength {
+		return nil, ErrInvalidKey
+	}
+
+	holder, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &model.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Holder:      holder,
+	}
+
+	now := time.Now()
+	record, err := uc.repo.Reserve(ctx, reservation, now.Add(-uc.retention), now.Add(-uc.lockTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		return reservation, nil
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+
+	if !record.Completed() {
+		return nil, ErrInProgress
+	}
+
+	return record, nil
+}
+
+// Complete stores the response of a request reserved with Begin.
+func (uc *UseCase) Complete(
+	ctx context.Context,
+	reservation *model.IdempotencyRecord,
+	statusCode int,
+	response []byte,
+) error {
+	return uc.repo.Complete(ctx, reservation.Key, reservation.Holder, statusCode, response)
+}
+
+// Release drops the reservation of a failed request so it can be retried.
+func (uc *UseCase) Release(ctx context.Context, reservation *model.IdempotencyRecord) error {
+	return uc.repo.Release(ctx, reservation.Key, reservation.Holder)
+}
+
+// Purge removes the keys older than the retention window.
+func (uc *UseCase) Purge(ctx context.Context) error {
+	return uc.repo.Purge(ctx, time.Now().Add(-uc.retention))
+}
+
+func randomHex(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
+
+func New(retention, lockTimeout time.Duration, repo Repo) *UseCase {
+	return &UseCase{
+		retention,
+		lockTimeout,
+		repo,
+	}
+}
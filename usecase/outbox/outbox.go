@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// EventPublisher sends domain events to their consumers. Events are only
+// removed from the outbox once Publish succeeds, so they are published
+// again after a failure.
+type EventPublisher interface {
+	Publish(ctx context.Context, events []model.OutboxEvent) error
+}
+
+// Publishers publishes the events to each of its publishers in turn. A
+// publisher which failed gets the events again, and so do the publishers
+// before it.
+type Publishers []EventPublisher
+
+func (p Publishers) Publish(ctx context.Context, events []model.OutboxEvent) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, events); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type Repo interface {
+	// Relay passes the oldest events of the outbox, at most limit, to
+	// publish and removes them once published. A single relay runs at a
+	// time, the others relay nothing.
+	Relay(ctx context.Context, limit int, publish func([]model.OutboxEvent) error) (int, error)
+}
+
+type UseCase struct {
+	repo      Repo
+	publisher EventPublisher
+	batchSize int
+}
+
+// Drain publishes the events of the outbox until it is empty. Events are
+// published in the order they were saved, which is the order of the
+// changes of each wallet.
+func (uc *UseCase) Drain(ctx context.Context) error {
+	publish := func(events []model.OutboxEvent) error {
+		return uc.publisher.Publish(ctx, events)
+	}
+
+	for {
+		published, err := uc.repo.Relay(ctx, uc.batchSize, publish)
+		if err != nil {
+			return err
+		}
+
+		if published < uc.batchSize {
+			return nil
+		}
+	}
+}
+
+func New(repo Repo, publisher EventPublisher, batchSize int) *UseCase {
+	return &UseCase{
+		repo,
+		publisher,
+		batchSize,
+	}
+}
@@ -0,0 +1,53 @@
+package wallet
+
+// Error is a domain error of the wallet use cases. Errors are compared
+// with errors.Is, the repository wraps the database errors it translates.
+type Error struct {
+	msg string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+var (
+	ErrWalletNotFound    = &Error{"wallet not found"}
+	ErrWalletExists      = &Error{"user already has a wallet of this type in this currency"}
+	ErrInsufficientFunds = &Error{"wallet balance not enough"}
+	ErrCurrencyMismatch  = &Error{"currency does not match the wallet"}
+	ErrFundScale         = &Error{"fund has too many decimal places"}
+	ErrSameWallet        = &Error{"cannot transfer to the same wallet"}
+	ErrConcurrentUpdate  = &Error{"wallet was updated concurrently, retry the request"}
+	ErrVersionMismatch   = &Error{"wallet is not at the expected version"}
+
+	ErrWalletClosed     = &Error{"wallet is closed"}
+	ErrWalletNotEmpty   = &Error{"wallet balance must be zero to close it"}
+	ErrWalletHeld       = &Error{"wallet has active holds"}
+	ErrSweepWallet      = &Error{"invalid sweep wallet"}
+	ErrWalletFrozen     = &Error{"wallet is frozen"}
+	ErrWalletBlocked    = &Error{"wallet is blocked for withdrawals"}
+	ErrWalletStatus     = &Error{"invalid wallet status"}
+	ErrStatusTransition = &Error{"wallet status change is not allowed"}
+	ErrStatusReason     = &Error{"status reason is required and at most 255 characters"}
+
+	ErrRateUnavailable = &Error{"exchange rate not available"}
+	ErrRateProvider    = &Error{"exchange rates cannot be loaded"}
+	ErrQuoteNotFound   = &Error{"quote not found"}
+	ErrQuoteExpired    = &Error{"quote has expired or was already used"}
+	ErrQuoteMismatch   = &Error{"quote does not match the transfer currencies"}
+	ErrQuoteCurrencies = &Error{"quote needs two different currencies"}
+	ErrConvertedAmount = &Error{"converted amount is too small"}
+
+	ErrHoldNotFound  = &Error{"hold not found"}
+	ErrHoldNotActive = &Error{"hold is not active"}
+	ErrHoldExpired   = &Error{"hold has expired"}
+	ErrHoldTTL       = &Error{"invalid hold ttl"}
+	ErrCaptureAmount = &Error{"capture amount exceeds the hold"}
+
+	ErrInvalidCursor = &Error{"invalid cursor"}
+
+	ErrQueueFull    = &Error{"too many pending requests, retry later"}
+	ErrTaskTimeout  = &Error{"request timed out"}
+	ErrShuttingDown = &Error{"service is shutting down"}
+	ErrJobNotFound  = &Error{"job not found"}
+)
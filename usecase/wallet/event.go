@@ -0,0 +1,50 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// Publisher delivers the balance events of committed changes to their
+// subscribers, it must not block.
+type Publisher interface {
+	Publish(events []model.BalanceEvent)
+}
+
+// Changes returns the balance events of a wallet after the event afterID,
+// oldest first and at most MaxPageSize at once.
+func (uc *UseCase) Changes(
+	ctx context.Context,
+	userID, walletID, afterID int64,
+) ([]model.BalanceEvent, error) {
+	entries, err := uc.repo.ListTransactions(ctx, userID, walletID, model.LedgerFilter{
+		AfterID: afterID,
+		Limit:   MaxPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return balanceEvents(entries), nil
+}
+
+// publish sends the balance events of the changes recorded for wallets.
+// The webhooks are notified by the outbox relay, which only sees the
+// committed changes.
+func (uc *UseCase) publish(wallets ...*model.Wallet) {
+	for _, wallet := range wallets {
+		if wallet != nil && len(wallet.Entries) > 0 {
+			uc.events.Publish(balanceEvents(wallet.Entries))
+		}
+	}
+}
+
+func balanceEvents(entries []model.LedgerEntry) []model.BalanceEvent {
+	events := make([]model.BalanceEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, model.NewBalanceEvent(entry))
+	}
+
+	return events
+}
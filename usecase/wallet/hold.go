@@ -0,0 +1,67 @@
+package wallet
+
+import (
+	"context"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+const (
+	// DefaultHoldTTL is the lifetime of a hold placed without ttl.
+	DefaultHoldTTL = 15 * time.Minute
+	// MaxHoldTTL is the maximum lifetime of a hold.
+	MaxHoldTTL = 24 * time.Hour
+)
+
+// Hold reserves funds of the available balance for ttl.
+func (uc *UseCase) Hold(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	ttl time.Duration,
+) (*model.Hold, error) {
+	if ttl == 0 {
+		ttl = DefaultHoldTTL
+	}
+	if ttl < 0 || ttl > MaxHoldTTL {
+		return nil, ErrHoldTTL
+	}
+
+	return uc.repo.Hold(ctx, userID, walletID, funds, time.Now().Add(ttl), guardBalance)
+}
+
+// Capture spends funds of a hold, the whole hold is captured when funds is nil.
+func (uc *UseCase) Capture(
+	ctx context.Context,
+	userID, walletID, holdID int64,
+	funds *model.Money,
+) (*model.Hold, error) {
+	hold, err := uc.repo.Capture(ctx, userID, walletID, holdID, funds, guardBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publish(hold.Wallet)
+	return hold, nil
+}
+
+func (uc *UseCase) Release(
+	ctx context.Context,
+	userID, walletID, holdID int64,
+) (*model.Hold, error) {
+	return uc.repo.Release(ctx, userID, walletID, holdID)
+}
+
+func (uc *UseCase) GetHold(
+	ctx context.Context,
+	userID, walletID, holdID int64,
+) (*model.Hold, error) {
+	return uc.repo.GetHold(ctx, userID, walletID, holdID)
+}
+
+// ReleaseExpiredHolds gives the funds of expired holds back to their wallets.
+func (uc *UseCase) ReleaseExpiredHolds(ctx context.Context) error {
+	_, err := uc.repo.ReleaseExpiredHolds(ctx)
+	return err
+}
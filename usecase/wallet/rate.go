@@ -0,0 +1,116 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// RateProvider provides the current exchange rates.
+type RateProvider interface {
+	// Rate returns the amount of to one unit of from is worth,
+	// ErrRateUnavailable when the provider has no rate for the pair.
+	Rate(ctx context.Context, from, to model.Currency) (model.Rate, error)
+}
+
+// Quote locks the current rate between two currencies for the quote ttl.
+func (uc *UseCase) Quote(
+	ctx context.Context,
+	from, to model.Currency,
+) (*model.Quote, error) {
+	if from == to {
+		return nil, ErrQuoteCurrencies
+	}
+
+	rate, err := uc.rate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &model.Quote{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         rate,
+		ExpiresAt:    time.Now().Add(uc.quoteTTL),
+	}
+	if err := uc.repo.CreateQuote(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// convert builds the transfer of request, converting the amount to the
+// currency of the target wallet. The quote is only checked here, the
+// repo uses it up within the transfer.
+func (uc *UseCase) convert(
+	ctx context.Context,
+	request model.TransferRequest,
+) (*model.Transfer, error) {
+	transfer := &model.Transfer{
+		FromWalletID: request.FromWalletID,
+		ToWalletID:   request.ToWalletID,
+		Amount:       request.Amount,
+		Currency:     request.Currency,
+		ToAmount:     request.Amount,
+		ToCurrency:   request.ToCurrency,
+		Rate:         model.OneRate(),
+	}
+	if transfer.ToCurrency == "" {
+		transfer.ToCurrency = transfer.Currency
+	}
+
+	if request.QuoteID != nil {
+		quote, err := uc.repo.GetQuote(ctx, *request.QuoteID)
+		if err != nil {
+			return nil, err
+		}
+
+		if quote.FromCurrency != transfer.Currency || quote.ToCurrency != transfer.ToCurrency {
+			return nil, ErrQuoteMismatch
+		}
+
+		if quote.UsedAt != nil || !time.Now().Before(quote.ExpiresAt) {
+			return nil, ErrQuoteExpired
+		}
+
+		transfer.QuoteID = &quote.ID
+		transfer.Rate = quote.Rate
+	} else if transfer.Currency != transfer.ToCurrency {
+		rate, err := uc.rate(ctx, transfer.Currency, transfer.ToCurrency)
+		if err != nil {
+			return nil, err
+		}
+		transfer.Rate = rate
+	}
+
+	if transfer.Currency == transfer.ToCurrency {
+		return transfer, nil
+	}
+
+	// the target wallet cannot hold less than its minor unit
+	transfer.ToAmount = transfer.ToCurrency.Round(transfer.Rate.Convert(transfer.Amount))
+	if transfer.ToAmount.IsZero() {
+		return nil, ErrConvertedAmount
+	}
+
+	return transfer, nil
+}
+
+// rate asks the provider for a rate, failures of the provider itself
+// are wrapped in ErrRateProvider.
+func (uc *UseCase) rate(ctx context.Context, from, to model.Currency) (model.Rate, error) {
+	rate, err := uc.rates.Rate(ctx, from, to)
+	if err != nil {
+		if errors.Is(err, ErrRateUnavailable) {
+			return model.Rate{}, err
+		}
+
+		return model.Rate{}, fmt.Errorf("%w: %v", ErrRateProvider, err)
+	}
+
+	return rate, nil
+}
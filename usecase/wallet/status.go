@@ -0,0 +1,102 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// transitions are the status changes allowed from each status.
+// Closed is final and only reached by closing an active wallet, so
+// blocked funds cannot be swept away.
+var transitions = map[model.WalletStatus][]model.WalletStatus{
+	model.WalletActive:          {model.WalletWithdrawBlocked, model.WalletFrozen, model.WalletClosed},
+	model.WalletWithdrawBlocked: {model.WalletActive, model.WalletFrozen},
+	model.WalletFrozen:          {model.WalletActive, model.WalletWithdrawBlocked},
+}
+
+// CanTransition reports whether a wallet may change from one status to another.
+func CanTransition(from, to model.WalletStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// guardBalance rejects the balance changes the wallet status does not allow.
+func guardBalance(wallet *model.Wallet, debit bool) error {
+	switch wallet.Status {
+	case model.WalletFrozen:
+		return ErrWalletFrozen
+	case model.WalletWithdrawBlocked:
+		if debit {
+			return ErrWalletBlocked
+		}
+	}
+
+	return nil
+}
+
+// guardVersion rejects the changes of a wallet which is not at one of
+// versions before guard, any version is accepted when versions is nil.
+func guardVersion(versions []int64, guard model.WalletGuard) model.WalletGuard {
+	if versions == nil {
+		return guard
+	}
+
+	return func(wallet *model.Wallet, debit bool) error {
+		for _, version := range versions {
+			if wallet.Version == version {
+				return guard(wallet, debit)
+			}
+		}
+
+		return ErrVersionMismatch
+	}
+}
+
+// guardTransition rejects the status changes to status the state machine does not allow.
+func guardTransition(status model.WalletStatus) model.WalletGuard {
+	return func(wallet *model.Wallet, _ bool) error {
+		if !CanTransition(wallet.Status, status) {
+			return ErrStatusTransition
+		}
+
+		return nil
+	}
+}
+
+// SetStatus changes the status of a wallet, wallets are closed with Close.
+func (uc *UseCase) SetStatus(
+	ctx context.Context,
+	walletID int64,
+	status model.WalletStatus,
+	reason string,
+) (*model.Wallet, error) {
+	if !status.Valid() || status == model.WalletClosed {
+		return nil, ErrWalletStatus
+	}
+
+	if reason == "" {
+		return nil, ErrStatusReason
+	}
+
+	wallet, err := uc.repo.SetStatus(ctx, walletID, status, reason, guardTransition(status))
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publish(wallet)
+	return wallet, nil
+}
+
+// ListStatusChanges returns the status history of a wallet.
+func (uc *UseCase) ListStatusChanges(
+	ctx context.Context,
+	walletID int64,
+) ([]model.WalletStatusChange, error) {
+	return uc.repo.ListStatusChanges(ctx, walletID)
+}
@@ -0,0 +1,103 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+// The tasks of the use case. Their queues are named after the task name
+// of the use case, e.g. "wallet_task.deposit".
+const (
+	TaskCreateWallet = "create_wallet"
+	TaskDeposit      = "deposit"
+	TaskWithdraw     = "withdraw"
+	TaskGetBalance   = "get_balance"
+)
+
+// TaskFunc is the work of a task, its result is the result of the job.
+type TaskFunc func(ctx context.Context) (interface{}, error)
+
+// TaskRunner runs tasks on a bounded pool of workers per task name. It
+// fails with ErrQueueFull when too many tasks of a name are waiting,
+// ErrTaskTimeout when a task does not finish in time and ErrShuttingDown
+// once it is stopped.
+type TaskRunner interface {
+	// Run executes fn and waits for its result.
+	Run(ctx context.Context, task string, fn TaskFunc) (interface{}, error)
+	// Submit queues fn as a job and returns at once.
+	Submit(task string, fn TaskFunc) (*model.Job, error)
+	// Job returns a submitted job, ErrJobNotFound once it is forgotten.
+	Job(id string) (*model.Job, error)
+}
+
+// CreateAsync queues the creation of a wallet, the job result is the wallet.
+func (uc *UseCase) CreateAsync(
+	userID int64,
+	walletType model.WalletType,
+	currency model.Currency,
+) (*model.Job, error) {
+	return uc.tasks.Submit(uc.task(TaskCreateWallet), walletTask(func(ctx context.Context) (*model.Wallet, error) {
+		return uc.create(ctx, userID, walletType, currency)
+	}))
+}
+
+// DepositAsync queues a Deposit, the job result is the wallet.
+func (uc *UseCase) DepositAsync(
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	ifMatch []int64,
+) (*model.Job, error) {
+	return uc.tasks.Submit(uc.task(TaskDeposit), walletTask(func(ctx context.Context) (*model.Wallet, error) {
+		return uc.deposit(ctx, userID, walletID, funds, currency, ifMatch)
+	}))
+}
+
+// WithdrawAsync queues a Withdraw, the job result is the wallet.
+func (uc *UseCase) WithdrawAsync(
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	ifMatch []int64,
+) (*model.Job, error) {
+	return uc.tasks.Submit(uc.task(TaskWithdraw), walletTask(func(ctx context.Context) (*model.Wallet, error) {
+		return uc.withdraw(ctx, userID, walletID, funds, currency, ifMatch)
+	}))
+}
+
+// Job returns a job queued by the use case.
+func (uc *UseCase) Job(ctx context.Context, id string) (*model.Job, error) {
+	return uc.tasks.Job(id)
+}
+
+// task is the queue name of a task of the use case.
+func (uc *UseCase) task(name string) string {
+	return uc.taskName + "." + name
+}
+
+// runWallet runs fn as task and returns its wallet.
+func (uc *UseCase) runWallet(
+	ctx context.Context,
+	task string,
+	fn func(ctx context.Context) (*model.Wallet, error),
+) (*model.Wallet, error) {
+	result, err := uc.tasks.Run(ctx, uc.task(task), walletTask(fn))
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*model.Wallet), nil
+}
+
+// walletTask is the task of fn, it has no result when fn fails.
+func walletTask(fn func(ctx context.Context) (*model.Wallet, error)) TaskFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		wallet, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return wallet, nil
+	}
+}
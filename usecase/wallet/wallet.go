@@ -2,127 +2,276 @@ package wallet
 
 import (
 	"context"
-	"errors"
-	"net/http"
-	"strings"
+	"encoding/base64"
+	"strconv"
+	"time"
 
 	"github.com/sysdevguru/bluelabs/model"
-	"github.com/sysdevguru/bluelabs/pkg"
-	"gorm.io/gorm"
 )
 
+// Repo stores the wallets. Its errors are the domain errors of this
+// package, database errors it cannot translate are returned as they are.
 type Repo interface {
-	Create(ctx context.Context, userID int64) (*model.Wallet, error)
-	Deposit(ctx context.Context, userID, walletID int64, funds float64) (*model.Wallet, error)
-	Withdraw(ctx context.Context, userID, walletID int64, funds float64) (*model.Wallet, error)
+	Create(ctx context.Context, userID int64, walletType model.WalletType, currency model.Currency) (*model.Wallet, error)
+	ListWallets(ctx context.Context, userID int64) ([]model.Wallet, error)
+	Deposit(ctx context.Context, userID, walletID int64, funds model.Money, currency model.Currency, guard model.WalletGuard) (*model.Wallet, error)
+	Withdraw(ctx context.Context, userID, walletID int64, funds model.Money, currency model.Currency, guard model.WalletGuard) (*model.Wallet, error)
+	Transfer(ctx context.Context, transfer *model.Transfer, guard model.WalletGuard) (*model.Transfer, error)
+	CreateQuote(ctx context.Context, quote *model.Quote) error
+	GetQuote(ctx context.Context, quoteID int64) (*model.Quote, error)
 	GetWallet(ctx context.Context, userID, walletID int64) (*model.Wallet, error)
+	Close(ctx context.Context, userID, walletID int64, reason string, sweepWalletID *int64, guard model.WalletGuard) (*model.Wallet, error)
+	SetStatus(ctx context.Context, walletID int64, status model.WalletStatus, reason string, guard model.WalletGuard) (*model.Wallet, error)
+	ListStatusChanges(ctx context.Context, walletID int64) ([]model.WalletStatusChange, error)
+	Hold(ctx context.Context, userID, walletID int64, funds model.Money, expiresAt time.Time, guard model.WalletGuard) (*model.Hold, error)
+	Capture(ctx context.Context, userID, walletID, holdID int64, funds *model.Money, guard model.WalletGuard) (*model.Hold, error)
+	Release(ctx context.Context, userID, walletID, holdID int64) (*model.Hold, error)
+	GetHold(ctx context.Context, userID, walletID, holdID int64) (*model.Hold, error)
+	ReleaseExpiredHolds(ctx context.Context) (int, error)
+	ListTransactions(ctx context.Context, userID, walletID int64, filter model.LedgerFilter) ([]model.LedgerEntry, error)
 }
 
+const (
+	// DefaultPageSize is the number of ledger entries returned when no limit is given.
+	DefaultPageSize = 50
+	// MaxPageSize is the maximum number of ledger entries returned at once.
+	MaxPageSize = 100
+)
+
 type UseCase struct {
 	taskName string
 	repo     Repo
+	rates    RateProvider
+	quoteTTL time.Duration
+	events   Publisher
+	tasks    TaskRunner
 }
 
 func (uc *UseCase) Create(
 	ctx context.Context,
 	userID int64,
+	walletType model.WalletType,
+	currency model.Currency,
 ) (*model.Wallet, error) {
-	wallet, err := uc.repo.Create(ctx, userID)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return nil, pkg.StatusError{
-				Code:   http.StatusConflict,
-				ErrMsg: pkg.ErrDuplicated,
-			}
-		}
+	return uc.runWallet(ctx, TaskCreateWallet, func(ctx context.Context) (*model.Wallet, error) {
+		return uc.create(ctx, userID, walletType, currency)
+	})
+}
 
-		return nil, pkg.StatusError{
-			Code:   http.StatusInternalServerError,
-			ErrMsg: err.Error(),
-		}
+func (uc *UseCase) create(
+	ctx context.Context,
+	userID int64,
+	walletType model.WalletType,
+	currency model.Currency,
+) (*model.Wallet, error) {
+	if walletType == "" {
+		walletType = model.WalletCash
 	}
 
-	return wallet, nil
+	return uc.repo.Create(ctx, userID, walletType, currency)
+}
+
+// ListWallets returns all wallets of a user with their balances.
+func (uc *UseCase) ListWallets(
+	ctx context.Context,
+	userID int64,
+) ([]model.Wallet, error) {
+	return uc.repo.ListWallets(ctx, userID)
 }
 
 func (uc *UseCase) GetWallet(
 	ctx context.Context,
 	userID, walletID int64,
 ) (*model.Wallet, error) {
-	wallet, err := uc.repo.GetWallet(ctx, userID, walletID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkg.StatusError{
-				Code:   http.StatusNotFound,
-				ErrMsg: pkg.ErrWalletNotFound,
-			}
-		}
+	return uc.runWallet(ctx, TaskGetBalance, func(ctx context.Context) (*model.Wallet, error) {
+		return uc.repo.GetWallet(ctx, userID, walletID)
+	})
+}
 
-		return nil, pkg.StatusError{
-			Code:   http.StatusInternalServerError,
-			ErrMsg: err.Error(),
+// Close closes a wallet, a remaining balance is swept to sweepWalletID.
+func (uc *UseCase) Close(
+	ctx context.Context,
+	userID, walletID int64,
+	reason string,
+	sweepWalletID *int64,
+) (*model.Wallet, error) {
+	// the closed wallet is debited by the sweep, the sweep wallet credited
+	guard := func(wallet *model.Wallet, debit bool) error {
+		if debit {
+			return guardTransition(model.WalletClosed)(wallet, debit)
 		}
+
+		return guardBalance(wallet, debit)
+	}
+
+	wallet, err := uc.repo.Close(ctx, userID, walletID, reason, sweepWalletID, guard)
+	if err != nil {
+		return nil, err
 	}
 
+	uc.publish(wallet)
 	return wallet, nil
 }
 
+// Deposit changes the balance of a wallet. ifMatch are the versions the
+// wallet must be at, it can be at any when ifMatch is nil.
 func (uc *UseCase) Deposit(
 	ctx context.Context,
 	userID, walletID int64,
-	funds float64,
+	funds model.Money,
+	currency model.Currency,
+	ifMatch []int64,
 ) (*model.Wallet, error) {
-	wallet, err := uc.repo.Deposit(ctx, userID, walletID, funds)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkg.StatusError{
-				Code:   http.StatusNotFound,
-				ErrMsg: pkg.ErrWalletNotFound,
-			}
-		}
+	return uc.runWallet(ctx, TaskDeposit, func(ctx context.Context) (*model.Wallet, error) {
+		return uc.deposit(ctx, userID, walletID, funds, currency, ifMatch)
+	})
+}
 
-		return nil, pkg.StatusError{
-			Code:   http.StatusInternalServerError,
-			ErrMsg: err.Error(),
-		}
+func (uc *UseCase) deposit(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	ifMatch []int64,
+) (*model.Wallet, error) {
+	wallet, err := uc.repo.Deposit(ctx, userID, walletID, funds, currency, guardVersion(ifMatch, guardBalance))
+	if err != nil {
+		return nil, err
 	}
 
+	uc.publish(wallet)
 	return wallet, nil
 }
 
+// Withdraw changes the balance of a wallet. ifMatch are the versions the
+// wallet must be at, it can be at any when ifMatch is nil.
 func (uc *UseCase) Withdraw(
 	ctx context.Context,
 	userID, walletID int64,
-	funds float64,
+	funds model.Money,
+	currency model.Currency,
+	ifMatch []int64,
 ) (*model.Wallet, error) {
-	wallet, err := uc.repo.Withdraw(ctx, userID, walletID, funds)
+	return uc.runWallet(ctx, TaskWithdraw, func(ctx context.Context) (*model.Wallet, error) {
+		return uc.withdraw(ctx, userID, walletID, funds, currency, ifMatch)
+	})
+}
+
+func (uc *UseCase) withdraw(
+	ctx context.Context,
+	userID, walletID int64,
+	funds model.Money,
+	currency model.Currency,
+	ifMatch []int64,
+) (*model.Wallet, error) {
+	wallet, err := uc.repo.Withdraw(ctx, userID, walletID, funds, currency, guardVersion(ifMatch, guardBalance))
 	if err != nil {
-		if err.Error() == pkg.ErrWalletBalance {
-			return nil, pkg.StatusError{
-				Code:   http.StatusBadRequest,
-				ErrMsg: err.Error(),
-			}
-		}
+		return nil, err
+	}
 
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkg.StatusError{
-				Code:   http.StatusNotFound,
-				ErrMsg: pkg.ErrWalletNotFound,
-			}
-		}
+	uc.publish(wallet)
+	return wallet, nil
+}
+
+// Transfer moves funds between two wallets. The amount is converted when
+// the currencies differ, with the rate of the quote or the current rate.
+func (uc *UseCase) Transfer(
+	ctx context.Context,
+	request model.TransferRequest,
+) (*model.Transfer, error) {
+	if request.FromWalletID == request.ToWalletID {
+		return nil, ErrSameWallet
+	}
+
+	transfer, err := uc.convert(ctx, request)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil, pkg.StatusError{
-			Code:   http.StatusInternalServerError,
-			ErrMsg: err.Error(),
+	transfer, err = uc.repo.Transfer(ctx, transfer, guardBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publish(transfer.FromWallet, transfer.ToWallet)
+	return transfer, nil
+}
+
+// ListTransactions returns a page of the wallet ledger, newest first.
+// cursor is the NextCursor of the previous page or empty for the first page.
+func (uc *UseCase) ListTransactions(
+	ctx context.Context,
+	userID, walletID int64,
+	filter model.LedgerFilter,
+	cursor string,
+) (*model.LedgerPage, error) {
+	if cursor != "" {
+		beforeID, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
 		}
+		filter.BeforeID = beforeID
 	}
 
-	return wallet, nil
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		filter.Limit = MaxPageSize
+	}
+	limit := filter.Limit
+
+	// fetch one more entry to find out whether there is a next page
+	filter.Limit++
+	entries, err := uc.repo.ListTransactions(ctx, userID, walletID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.LedgerPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = encodeCursor(page.Entries[limit-1].ID)
+	}
+
+	return page, nil
+}
+
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return id, nil
 }
 
-func New(taskName string, repo Repo) *UseCase {
+func New(
+	taskName string,
+	repo Repo,
+	rates RateProvider,
+	quoteTTL time.Duration,
+	events Publisher,
+	tasks TaskRunner,
+) *UseCase {
 	return &UseCase{
 		taskName,
 		repo,
+		rates,
+		quoteTTL,
+		events,
+		tasks,
 	}
 }
@@ -3,7 +3,11 @@ package wallet_test
 import (
 	"context"
 	"os"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/sysdevguru/bluelabs/model"
 	"github.com/sysdevguru/bluelabs/pkg"
 	. "github.com/sysdevguru/bluelabs/usecase/wallet"
 
@@ -15,9 +19,11 @@ import (
 var _ = Describe("Wallet", func() {
 	var (
 		uc       *UseCase
+		repo     *pkg.GormRepo
 		db       *gorm.DB
 		ctx      context.Context
 		walletID int64
+		targetID int64
 	)
 
 	BeforeEach(func() {
@@ -30,9 +36,20 @@ var _ = Describe("Wallet", func() {
 		db, err = pkg.NewGormWithPostgres(cfg)
 		assert.NoError(GinkgoT(), err)
 
+		rates, err := pkg.NewStaticRateProvider("EUR", map[string]string{
+			"GBP": "0.86",
+			"SEK": "11.5",
+		})
+		assert.NoError(GinkgoT(), err)
+
+		repo = pkg.NewRepo(db)
 		uc = New(
 			"wallet_task_test",
-			pkg.NewRepo(db),
+			repo,
+			rates,
+			time.Minute,
+			pkg.NewEventBus(),
+			pkg.NewTaskQueue(cfg.Tasks),
 		)
 	})
 
@@ -43,53 +60,53 @@ var _ = Describe("Wallet", func() {
 
 	Context("Create wallet", func() {
 		It("for user 1", func() {
-			wallet, err := uc.Create(ctx, 1)
+			wallet, err := uc.Create(ctx, 1, model.WalletCash, "EUR")
 			walletID = wallet.ID
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), 1, int(wallet.UserID))
-			assert.Equal(GinkgoT(), 0.00, wallet.Balance)
+			assert.Equal(GinkgoT(), "0", wallet.Balance.String())
 		})
 	})
 
 	Context("Deposit", func() {
 		It("from non-existing wallet", func() {
-			_, err := uc.Deposit(ctx, 1, 1000, 10)
-			assert.Equal(GinkgoT(), "wallet not found", err.Error())
+			_, err := uc.Deposit(ctx, 1, 1000, model.RequireMoney("10"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotFound)
 		})
 
 		It("as expected", func() {
-			wallet, err := uc.Deposit(ctx, 1, walletID, 100.00)
+			wallet, err := uc.Deposit(ctx, 1, walletID, model.RequireMoney("100.00"), "EUR", nil)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), 1, int(wallet.UserID))
 			assert.Equal(GinkgoT(), walletID, wallet.ID)
-			assert.Equal(GinkgoT(), 100.00, wallet.Balance)
+			assert.Equal(GinkgoT(), "100", wallet.Balance.String())
 		})
 	})
 
 	Context("Withdraw", func() {
 		It("from non-existing wallet", func() {
-			_, err := uc.Withdraw(ctx, 1, 1000, 10)
-			assert.Equal(GinkgoT(), "wallet not found", err.Error())
+			_, err := uc.Withdraw(ctx, 1, 1000, model.RequireMoney("10"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotFound)
 		})
 
 		It("more than balance", func() {
-			_, err := uc.Withdraw(ctx, 1, walletID, 1000.00)
-			assert.Equal(GinkgoT(), "wallet balance not enough", err.Error())
+			_, err := uc.Withdraw(ctx, 1, walletID, model.RequireMoney("1000.00"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrInsufficientFunds)
 		})
 
 		It("as expected", func() {
-			wallet, err := uc.Withdraw(ctx, 1, walletID, 35.00)
+			wallet, err := uc.Withdraw(ctx, 1, walletID, model.RequireMoney("35.00"), "EUR", nil)
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), 1, int(wallet.UserID))
 			assert.Equal(GinkgoT(), walletID, wallet.ID)
-			assert.Equal(GinkgoT(), 65.00, wallet.Balance)
+			assert.Equal(GinkgoT(), "65", wallet.Balance.String())
 		})
 	})
 
 	Context("Get Wallet", func() {
 		It("of non-existing user", func() {
 			_, err := uc.GetWallet(ctx, 12, walletID)
-			assert.Equal(GinkgoT(), "wallet not found", err.Error())
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotFound)
 		})
 
 		It("of user 1", func() {
@@ -97,7 +114,504 @@ var _ = Describe("Wallet", func() {
 			assert.NoError(GinkgoT(), err)
 			assert.Equal(GinkgoT(), 1, int(wallet.UserID))
 			assert.Equal(GinkgoT(), walletID, wallet.ID)
-			assert.Equal(GinkgoT(), 65.00, wallet.Balance)
+			assert.Equal(GinkgoT(), "65", wallet.Balance.String())
+		})
+	})
+
+	Context("Ledger", func() {
+		It("of non-existing user", func() {
+			_, err := repo.ListTransactions(ctx, 12, walletID, model.LedgerFilter{})
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotFound)
+		})
+
+		It("records every balance change", func() {
+			entries, err := repo.ListTransactions(ctx, 1, walletID, model.LedgerFilter{})
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), entries, 2)
+
+			assert.Equal(GinkgoT(), model.ActionWithdraw, entries[0].Action)
+			assert.Equal(GinkgoT(), "35", entries[0].Amount.String())
+			assert.Equal(GinkgoT(), "65", entries[0].BalanceAfter.String())
+
+			assert.Equal(GinkgoT(), model.ActionDeposit, entries[1].Action)
+			assert.Equal(GinkgoT(), "100", entries[1].Amount.String())
+			assert.Equal(GinkgoT(), "100", entries[1].BalanceAfter.String())
+		})
+	})
+
+	Context("List transactions", func() {
+		It("of non-existing user", func() {
+			_, err := uc.ListTransactions(ctx, 12, walletID, model.LedgerFilter{}, "")
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotFound)
+		})
+
+		It("with invalid cursor", func() {
+			_, err := uc.ListTransactions(ctx, 1, walletID, model.LedgerFilter{}, "invalid")
+			assert.ErrorIs(GinkgoT(), err, ErrInvalidCursor)
+		})
+
+		It("filtered by action", func() {
+			page, err := uc.ListTransactions(ctx, 1, walletID, model.LedgerFilter{Action: model.ActionDeposit}, "")
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), page.Entries, 1)
+			assert.Equal(GinkgoT(), model.ActionDeposit, page.Entries[0].Action)
+			assert.Empty(GinkgoT(), page.NextCursor)
+		})
+
+		It("page by page", func() {
+			page, err := uc.ListTransactions(ctx, 1, walletID, model.LedgerFilter{Limit: 1}, "")
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), page.Entries, 1)
+			assert.Equal(GinkgoT(), model.ActionWithdraw, page.Entries[0].Action)
+			assert.NotEmpty(GinkgoT(), page.NextCursor)
+
+			page, err = uc.ListTransactions(ctx, 1, walletID, model.LedgerFilter{Limit: 1}, page.NextCursor)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), page.Entries, 1)
+			assert.Equal(GinkgoT(), model.ActionDeposit, page.Entries[0].Action)
+			assert.Empty(GinkgoT(), page.NextCursor)
+		})
+	})
+
+	Context("Transfer", func() {
+		It("to another user", func() {
+			wallet, err := uc.Create(ctx, 11, model.WalletCash, "EUR")
+			assert.NoError(GinkgoT(), err)
+			targetID = wallet.ID
+		})
+
+		It("to the same wallet", func() {
+			_, err := uc.Transfer(ctx, model.TransferRequest{FromWalletID: walletID, ToWalletID: walletID, Amount: model.RequireMoney("10"), Currency: "EUR"})
+			assert.ErrorIs(GinkgoT(), err, ErrSameWallet)
+		})
+
+		It("to non-existing wallet", func() {
+			_, err := uc.Transfer(ctx, model.TransferRequest{FromWalletID: walletID, ToWalletID: 1000, Amount: model.RequireMoney("10"), Currency: "EUR"})
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotFound)
+		})
+
+		It("more than balance", func() {
+			_, err := uc.Transfer(ctx, model.TransferRequest{FromWalletID: walletID, ToWalletID: targetID, Amount: model.RequireMoney("1000"), Currency: "EUR"})
+			assert.ErrorIs(GinkgoT(), err, ErrInsufficientFunds)
+		})
+
+		It("as expected", func() {
+			transfer, err := uc.Transfer(ctx, model.TransferRequest{FromWalletID: walletID, ToWalletID: targetID, Amount: model.RequireMoney("15.50"), Currency: "EUR"})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "49.5", transfer.FromWallet.Balance.String())
+			assert.Equal(GinkgoT(), "15.5", transfer.ToWallet.Balance.String())
+		})
+
+		It("records both legs", func() {
+			page, err := uc.ListTransactions(ctx, 11, targetID, model.LedgerFilter{}, "")
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), page.Entries, 1)
+			assert.Equal(GinkgoT(), model.ActionTransferIn, page.Entries[0].Action)
+
+			page, err = uc.ListTransactions(ctx, 1, walletID, model.LedgerFilter{Limit: 1}, "")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.ActionTransferOut, page.Entries[0].Action)
+			assert.Equal(GinkgoT(), "15.5", page.Entries[0].Amount.String())
+		})
+	})
+
+	Context("Holds", func() {
+		var holdID int64
+
+		It("with invalid ttl", func() {
+			_, err := uc.Hold(ctx, 1, walletID, model.RequireMoney("10"), 25*time.Hour)
+			assert.ErrorIs(GinkgoT(), err, ErrHoldTTL)
+		})
+
+		It("more than balance", func() {
+			_, err := uc.Hold(ctx, 1, walletID, model.RequireMoney("100"), 0)
+			assert.ErrorIs(GinkgoT(), err, ErrInsufficientFunds)
+		})
+
+		It("as expected", func() {
+			hold, err := uc.Hold(ctx, 1, walletID, model.RequireMoney("40"), 0)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.HoldActive, hold.Status)
+			assert.Equal(GinkgoT(), "40", hold.Wallet.Held.String())
+			assert.Equal(GinkgoT(), "9.5", hold.Wallet.Available().String())
+			holdID = hold.ID
+		})
+
+		It("withdraw more than available", func() {
+			_, err := uc.Withdraw(ctx, 1, walletID, model.RequireMoney("10"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrInsufficientFunds)
+		})
+
+		It("capture more than held", func() {
+			funds := model.RequireMoney("41")
+			_, err := uc.Capture(ctx, 1, walletID, holdID, &funds)
+			assert.ErrorIs(GinkgoT(), err, ErrCaptureAmount)
+		})
+
+		It("capture partially", func() {
+			funds := model.RequireMoney("25")
+			hold, err := uc.Capture(ctx, 1, walletID, holdID, &funds)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.HoldCaptured, hold.Status)
+			assert.Equal(GinkgoT(), "25", hold.Captured.String())
+			assert.Equal(GinkgoT(), "24.5", hold.Wallet.Balance.String())
+			assert.Equal(GinkgoT(), "0", hold.Wallet.Held.String())
+		})
+
+		It("release captured hold", func() {
+			_, err := uc.Release(ctx, 1, walletID, holdID)
+			assert.ErrorIs(GinkgoT(), err, ErrHoldNotActive)
+		})
+
+		It("release as expected", func() {
+			hold, err := uc.Hold(ctx, 1, walletID, model.RequireMoney("5"), time.Minute)
+			assert.NoError(GinkgoT(), err)
+
+			hold, err = uc.Release(ctx, 1, walletID, hold.ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.HoldReleased, hold.Status)
+			assert.Equal(GinkgoT(), "0", hold.Wallet.Held.String())
+		})
+
+		It("expires", func() {
+			hold, err := uc.Hold(ctx, 1, walletID, model.RequireMoney("5"), time.Millisecond)
+			assert.NoError(GinkgoT(), err)
+			time.Sleep(10 * time.Millisecond)
+
+			_, err = uc.Capture(ctx, 1, walletID, hold.ID, nil)
+			assert.ErrorIs(GinkgoT(), err, ErrHoldExpired)
+
+			err = uc.ReleaseExpiredHolds(ctx)
+			assert.NoError(GinkgoT(), err)
+
+			hold, err = uc.GetHold(ctx, 1, walletID, hold.ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.HoldExpired, hold.Status)
+
+			wallet, err := uc.GetWallet(ctx, 1, walletID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "0", wallet.Held.String())
+		})
+	})
+
+	Context("Currencies", func() {
+		It("duplicated wallet", func() {
+			_, err := uc.Create(ctx, 1, model.WalletCash, "EUR")
+			assert.ErrorIs(GinkgoT(), err, ErrWalletExists)
+		})
+
+		It("wallet in another currency", func() {
+			wallet, err := uc.Create(ctx, 1, model.WalletCash, "GBP")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.Currency("GBP"), wallet.Currency)
+		})
+
+		It("deposit in another currency", func() {
+			_, err := uc.Deposit(ctx, 1, walletID, model.RequireMoney("10"), "GBP", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrCurrencyMismatch)
+		})
+
+		It("transfer between currencies", func() {
+			wallet, err := uc.Create(ctx, 11, model.WalletCash, "SEK")
+			assert.NoError(GinkgoT(), err)
+
+			_, err = uc.Transfer(ctx, model.TransferRequest{FromWalletID: walletID, ToWalletID: wallet.ID, Amount: model.RequireMoney("1"), Currency: "EUR"})
+			assert.ErrorIs(GinkgoT(), err, ErrCurrencyMismatch)
+		})
+
+		It("more decimal places than the currency", func() {
+			_, err := uc.Deposit(ctx, 1, walletID, model.RequireMoney("0.001"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrFundScale)
+		})
+	})
+
+	Context("Wallet types", func() {
+		It("bonus wallet next to cash", func() {
+			wallet, err := uc.Create(ctx, 1, model.WalletBonus, "EUR")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.WalletBonus, wallet.Type)
+
+			_, err = uc.Create(ctx, 1, model.WalletBonus, "EUR")
+			assert.ErrorIs(GinkgoT(), err, ErrWalletExists)
+		})
+
+		It("defaults to cash", func() {
+			wallet, err := uc.Create(ctx, 13, "", "EUR")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.WalletCash, wallet.Type)
+		})
+
+		It("lists the wallets of a user", func() {
+			wallets, err := uc.ListWallets(ctx, 1)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), wallets, 3)
+			assert.Equal(GinkgoT(), walletID, wallets[0].ID)
+			assert.Equal(GinkgoT(), model.WalletBonus, wallets[2].Type)
+
+			wallets, err = uc.ListWallets(ctx, 1000)
+			assert.NoError(GinkgoT(), err)
+			assert.Empty(GinkgoT(), wallets)
+		})
+	})
+
+	Context("Conversion", func() {
+		var sekWalletID int64
+
+		It("to a wallet in another currency", func() {
+			wallet, err := uc.Create(ctx, 12, model.WalletCash, "SEK")
+			assert.NoError(GinkgoT(), err)
+			sekWalletID = wallet.ID
+
+			transfer, err := uc.Transfer(ctx, model.TransferRequest{
+				FromWalletID: walletID,
+				ToWalletID:   sekWalletID,
+				Amount:       model.RequireMoney("1"),
+				Currency:     "EUR",
+				ToCurrency:   "SEK",
+			})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "11.5", transfer.Rate.String())
+			assert.Equal(GinkgoT(), "11.5", transfer.ToAmount.String())
+			assert.Equal(GinkgoT(), "11.5", transfer.ToWallet.Balance.String())
+		})
+
+		It("records the rate on both legs", func() {
+			page, err := uc.ListTransactions(ctx, 12, sekWalletID, model.LedgerFilter{}, "")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "11.5", page.Entries[0].Rate.String())
+
+			page, err = uc.ListTransactions(ctx, 1, walletID, model.LedgerFilter{Limit: 1}, "")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "1", page.Entries[0].Amount.String())
+			assert.Equal(GinkgoT(), "11.5", page.Entries[0].Rate.String())
+		})
+
+		It("with a quote", func() {
+			quote, err := uc.Quote(ctx, "EUR", "SEK")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "11.5", quote.Rate.String())
+
+			request := model.TransferRequest{
+				FromWalletID: walletID,
+				ToWalletID:   sekWalletID,
+				Amount:       model.RequireMoney("1"),
+				Currency:     "EUR",
+				ToCurrency:   "SEK",
+				QuoteID:      &quote.ID,
+			}
+			transfer, err := uc.Transfer(ctx, request)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), quote.ID, *transfer.QuoteID)
+
+			_, err = uc.Transfer(ctx, request)
+			assert.ErrorIs(GinkgoT(), err, ErrQuoteExpired)
+		})
+
+		It("with a quote of other currencies", func() {
+			quote, err := uc.Quote(ctx, "EUR", "GBP")
+			assert.NoError(GinkgoT(), err)
+
+			_, err = uc.Transfer(ctx, model.TransferRequest{
+				FromWalletID: walletID,
+				ToWalletID:   sekWalletID,
+				Amount:       model.RequireMoney("1"),
+				Currency:     "EUR",
+				ToCurrency:   "SEK",
+				QuoteID:      &quote.ID,
+			})
+			assert.ErrorIs(GinkgoT(), err, ErrQuoteMismatch)
+		})
+
+		It("without a rate", func() {
+			_, err := uc.Quote(ctx, "EUR", "JPY")
+			assert.ErrorIs(GinkgoT(), err, ErrRateUnavailable)
+		})
+	})
+
+	Context("Close", func() {
+		var closedID, cashID, bonusID int64
+
+		It("empty wallet", func() {
+			wallet, err := uc.Create(ctx, 14, model.WalletCash, "EUR")
+			assert.NoError(GinkgoT(), err)
+			closedID = wallet.ID
+
+			wallet, err = uc.Close(ctx, 14, closedID, "user request", nil)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), wallet.Closed())
+			assert.Equal(GinkgoT(), "user request", wallet.CloseReason)
+		})
+
+		It("closed wallet", func() {
+			_, err := uc.GetWallet(ctx, 14, closedID)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletClosed)
+
+			_, err = uc.Deposit(ctx, 14, closedID, model.RequireMoney("10"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletClosed)
+
+			_, err = uc.Close(ctx, 14, closedID, "", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletClosed)
+		})
+
+		It("wallet with balance", func() {
+			wallet, err := uc.Create(ctx, 14, model.WalletCash, "EUR")
+			assert.NoError(GinkgoT(), err)
+			cashID = wallet.ID
+
+			wallet, err = uc.Create(ctx, 14, model.WalletBonus, "EUR")
+			assert.NoError(GinkgoT(), err)
+			bonusID = wallet.ID
+
+			_, err = uc.Deposit(ctx, 14, cashID, model.RequireMoney("10"), "EUR", nil)
+			assert.NoError(GinkgoT(), err)
+
+			_, err = uc.Close(ctx, 14, cashID, "", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletNotEmpty)
+
+			_, err = uc.Close(ctx, 14, cashID, "", &closedID)
+			assert.ErrorIs(GinkgoT(), err, ErrSweepWallet)
+		})
+
+		It("sweeps the balance", func() {
+			wallet, err := uc.Close(ctx, 14, cashID, "", &bonusID)
+			assert.NoError(GinkgoT(), err)
+			assert.True(GinkgoT(), wallet.Closed())
+			assert.Equal(GinkgoT(), "0", wallet.Balance.String())
+
+			wallet, err = uc.GetWallet(ctx, 14, bonusID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "10", wallet.Balance.String())
+		})
+	})
+
+	Context("Status", func() {
+		var frozenID int64
+
+		It("allowed transitions", func() {
+			assert.True(GinkgoT(), CanTransition(model.WalletActive, model.WalletFrozen))
+			assert.True(GinkgoT(), CanTransition(model.WalletFrozen, model.WalletWithdrawBlocked))
+			assert.False(GinkgoT(), CanTransition(model.WalletFrozen, model.WalletClosed))
+			assert.False(GinkgoT(), CanTransition(model.WalletClosed, model.WalletActive))
+		})
+
+		It("without reason", func() {
+			_, err := uc.SetStatus(ctx, walletID, model.WalletFrozen, "")
+			assert.ErrorIs(GinkgoT(), err, ErrStatusReason)
+		})
+
+		It("to closed", func() {
+			_, err := uc.SetStatus(ctx, walletID, model.WalletClosed, "fraud")
+			assert.ErrorIs(GinkgoT(), err, ErrWalletStatus)
+		})
+
+		It("blocks withdrawals", func() {
+			wallet, err := uc.Create(ctx, 15, model.WalletCash, "EUR")
+			assert.NoError(GinkgoT(), err)
+			frozenID = wallet.ID
+
+			_, err = uc.Deposit(ctx, 15, frozenID, model.RequireMoney("10"), "EUR", nil)
+			assert.NoError(GinkgoT(), err)
+
+			wallet, err = uc.SetStatus(ctx, frozenID, model.WalletWithdrawBlocked, "pending investigation")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.WalletWithdrawBlocked, wallet.Status)
+
+			_, err = uc.Withdraw(ctx, 15, frozenID, model.RequireMoney("1"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletBlocked)
+
+			wallet, err = uc.Deposit(ctx, 15, frozenID, model.RequireMoney("1"), "EUR", nil)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), "11", wallet.Balance.String())
+		})
+
+		It("freezes everything", func() {
+			_, err := uc.SetStatus(ctx, frozenID, model.WalletFrozen, "confirmed investigation")
+			assert.NoError(GinkgoT(), err)
+
+			_, err = uc.Deposit(ctx, 15, frozenID, model.RequireMoney("1"), "EUR", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletFrozen)
+
+			_, err = uc.Hold(ctx, 15, frozenID, model.RequireMoney("1"), time.Minute)
+			assert.ErrorIs(GinkgoT(), err, ErrWalletFrozen)
+
+			_, err = uc.Close(ctx, 15, frozenID, "", nil)
+			assert.ErrorIs(GinkgoT(), err, ErrStatusTransition)
+		})
+
+		It("records the changes", func() {
+			wallet, err := uc.SetStatus(ctx, frozenID, model.WalletActive, "cleared")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), model.WalletActive, wallet.Status)
+
+			changes, err := uc.ListStatusChanges(ctx, frozenID)
+			assert.NoError(GinkgoT(), err)
+			assert.Len(GinkgoT(), changes, 3)
+			assert.Equal(GinkgoT(), model.WalletActive, changes[0].FromStatus)
+			assert.Equal(GinkgoT(), model.WalletWithdrawBlocked, changes[0].ToStatus)
+			assert.Equal(GinkgoT(), "pending investigation", changes[0].Reason)
+		})
+	})
+
+	Context("Versions", func() {
+		It("are incremented by every change", func() {
+			wallet, err := uc.Create(ctx, 16, model.WalletCash, "EUR")
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(1), wallet.Version)
+
+			wallet, err = uc.Deposit(ctx, 16, wallet.ID, model.RequireMoney("10"), "EUR", []int64{1})
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(2), wallet.Version)
+
+			_, err = uc.Withdraw(ctx, 16, wallet.ID, model.RequireMoney("1"), "EUR", []int64{1})
+			assert.ErrorIs(GinkgoT(), err, ErrVersionMismatch)
+
+			wallet, err = uc.GetWallet(ctx, 16, wallet.ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), int64(2), wallet.Version)
+			assert.Equal(GinkgoT(), "10", wallet.Balance.String())
+		})
+
+		It("let optimistic changes retry on conflicts", func() {
+			optimistic := New(
+				"wallet_task_test",
+				pkg.NewOptimisticRepo(db, 20),
+				nil,
+				time.Minute,
+				pkg.NewEventBus(),
+				pkg.NewTaskQueue(pkg.Tasks{Workers: 8, QueueDepth: 8, Timeout: 10 * time.Second}),
+			)
+
+			wallet, err := optimistic.Create(ctx, 17, model.WalletCash, "EUR")
+			assert.NoError(GinkgoT(), err)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 8)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					_, err := optimistic.Deposit(ctx, 17, wallet.ID, model.RequireMoney("1"), "EUR", nil)
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			// a deposit only fails once it ran out of retries
+			deposits := 0
+			for err := range errs {
+				if err == nil {
+					deposits++
+					continue
+				}
+				assert.ErrorIs(GinkgoT(), err, ErrConcurrentUpdate)
+			}
+
+			wallet, err = optimistic.GetWallet(ctx, 17, wallet.ID)
+			assert.NoError(GinkgoT(), err)
+			assert.Equal(GinkgoT(), strconv.Itoa(deposits), wallet.Balance.String())
+			assert.Equal(GinkgoT(), int64(1+deposits), wallet.Version)
 		})
 	})
 })
@@ -0,0 +1,18 @@
+package webhook
+
+// Error is a domain error of the webhook use cases, compare with errors.Is.
+type Error struct {
+	msg string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+var (
+	ErrWebhookNotFound  = &Error{"webhook not found"}
+	ErrDeliveryNotFound = &Error{"webhook delivery not found"}
+	ErrWebhookURL       = &Error{"webhook url must be an absolute http or https url"}
+	ErrWebhookEvents    = &Error{"invalid webhook events"}
+	ErrDeliveryStatus   = &Error{"invalid delivery status"}
+)
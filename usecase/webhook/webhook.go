@@ -0,0 +1,411 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sysdevguru/bluelabs/model"
+)
+
+const (
+	// IDHeader carries the id of the delivered event, the same for every
+	// attempt so receivers can drop duplicates.
+	IDHeader = "X-Webhook-Id"
+	// TimestampHeader carries the unix time the payload was signed at.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader carries the Signature of the payload.
+	SignatureHeader = "X-Webhook-Signature"
+
+	// MaxDeliveries is the number of deliveries listed at most.
+	MaxDeliveries = 100
+
+	// batchSize is the number of deliveries attempted at once.
+	batchSize = 20
+)
+
+// notifiedActions are the ledger actions partners are notified of.
+var notifiedActions = map[model.ActionValue]model.WalletEventType{
+	model.ActionDeposit:  model.EventDeposit,
+	model.ActionWithdraw: model.EventWithdraw,
+}
+
+type Repo interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	List(ctx context.Context) ([]model.Webhook, error)
+	Get(ctx context.Context, webhookID int64) (*model.Webhook, error)
+	Delete(ctx context.Context, webhookID int64) error
+	// Enqueue stores the deliveries, skipping the ones of an event already
+	// queued for the webhook.
+	Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error
+	Wallets(ctx context.Context, walletIDs []int64) ([]model.Wallet, error)
+	// Claim returns up to limit deliveries due at now with their webhook,
+	// they are not due again before leaseUntil.
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, delivery *model.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID int64, status model.DeliveryStatus) ([]model.WebhookDelivery, error)
+	Replay(ctx context.Context, webhookID, deliveryID int64, at time.Time) (*model.WebhookDelivery, error)
+}
+
+// Sender posts a payload to a webhook url and returns the response status.
+type Sender interface {
+	Send(ctx context.Context, url string, header map[string]string, body []byte) (int, error)
+}
+
+// Retry is the schedule of the failed deliveries.
+type Retry struct {
+	// Base is the delay before the second attempt, it doubles after
+	// every failed attempt up to Max.
+	Base time.Duration
+	Max  time.Duration
+	// Attempts is the number of attempts before a delivery is dead.
+	Attempts int
+}
+
+type UseCase struct {
+	repo    Repo
+	sender  Sender
+	retry   Retry
+	timeout time.Duration
+}
+
+// Signature is the HMAC-SHA256 of the timestamp and the payload keyed by
+// the secret of the webhook, as "sha256=" and its hex encoding.
+func Signature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Create registers a webhook with a new secret.
+func (uc *UseCase) Create(ctx context.Context, request model.WebhookRequest) (*model.Webhook, error) {
+	target, err := url.Parse(request.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, ErrWebhookURL
+	}
+
+	events := model.WalletEventTypes{}
+	for _, eventType := range request.Events {
+		if !eventType.Valid() {
+			return nil, ErrWebhookEvents
+		}
+		if !events.Has(eventType) {
+			events = append(events, eventType)
+		}
+	}
+	if len(events) == 0 {
+		return nil, ErrWebhookEvents
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		URL:    request.URL,
+		Events: events,
+		Secret: secret,
+	}
+
+	return webhook, uc.repo.Create(ctx, webhook)
+}
+
+// List returns the webhooks without their secret.
+func (uc *UseCase) List(ctx context.Context) ([]model.Webhook, error) {
+	webhooks, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// Get returns a webhook without its secret.
+func (uc *UseCase) Get(ctx context.Context, webhookID int64) (*model.Webhook, error) {
+	webhook, err := uc.repo.Get(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// Delete removes a webhook and its deliveries.
+func (uc *UseCase) Delete(ctx context.Context, webhookID int64) error {
+	return uc.repo.Delete(ctx, webhookID)
+}
+
+// ListDeliveries returns the latest deliveries of a webhook, newest first. All
+// deliveries are returned when status is empty.
+func (uc *UseCase) ListDeliveries(
+	ctx context.Context,
+	webhookID int64,
+	status model.DeliveryStatus,
+) ([]model.WebhookDelivery, error) {
+	if status != "" && !status.Valid() {
+		return nil, ErrDeliveryStatus
+	}
+
+	if _, err := uc.repo.Get(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.ListDeliveries(ctx, webhookID, status)
+}
+
+// Replay sends a delivery again whatever its status, with a new set of attempts.
+func (uc *UseCase) Replay(ctx context.Context, webhookID, deliveryID int64) (*model.WebhookDelivery, error) {
+	return uc.repo.Replay(ctx, webhookID, deliveryID, time.Now())
+}
+
+// Publish queues the outbox events for the webhooks, it is a publisher of
+// the outbox relay so only committed changes are delivered. The id of a
+// webhook event is derived from its outbox event, an event the relay
+// publishes again is not queued twice.
+func (uc *UseCase) Publish(ctx context.Context, events []model.OutboxEvent) error {
+	webhookEvents := []model.WebhookEvent{}
+	walletIDs := []int64{}
+	for _, event := range events {
+		walletEvent, ok, err := newWalletEvent(event)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		webhookEvents = append(webhookEvents, model.WebhookEvent{
+			ID:          fmt.Sprintf("%032x", event.ID),
+			CreatedAt:   event.CreatedAt,
+			WalletEvent: walletEvent,
+		})
+		walletIDs = append(walletIDs, event.WalletID)
+	}
+
+	if len(webhookEvents) == 0 {
+		return nil
+	}
+
+	wallets, err := uc.repo.Wallets(ctx, walletIDs)
+	if err != nil {
+		return err
+	}
+
+	// the events carry the wallet as it is now, with the balance or the
+	// status the event changed it to
+	byID := map[int64]model.Wallet{}
+	for _, wallet := range wallets {
+		byID[wallet.ID] = wallet
+	}
+	for i := range webhookEvents {
+		event := &webhookEvents[i]
+		wallet, ok := byID[event.Wallet.ID]
+		if !ok {
+			continue
+		}
+
+		if event.Entry != nil {
+			wallet.Balance = event.Wallet.Balance
+		} else {
+			wallet.Status = event.Wallet.Status
+			wallet.StatusReason = event.Wallet.StatusReason
+		}
+		event.Wallet = wallet
+	}
+
+	return uc.Enqueue(ctx, webhookEvents)
+}
+
+// Enqueue queues events for the webhooks subscribed to their type.
+func (uc *UseCase) Enqueue(ctx context.Context, events []model.WebhookEvent) error {
+	webhooks, err := uc.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := []model.WebhookDelivery{}
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		for _, webhook := range webhooks {
+			if !webhook.Events.Has(event.Type) {
+				continue
+			}
+
+			deliveries = append(deliveries, model.WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventID:       event.ID,
+				EventType:     event.Type,
+				Payload:       payload,
+				Status:        model.DeliveryPending,
+				NextAttemptAt: now,
+			})
+		}
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return uc.repo.Enqueue(ctx, deliveries)
+}
+
+// Deliver attempts the due deliveries. A failed delivery is retried with
+// an exponential backoff until it runs out of attempts and is dead.
+func (uc *UseCase) Deliver(ctx context.Context) error {
+	now := time.Now()
+	// a claimed delivery is due again if its attempt is not saved in time
+	deliveries, err := uc.repo.Claim(ctx, now, now.Add(2*uc.timeout), batchSize)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, len(deliveries))
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *model.WebhookDelivery) {
+			defer wg.Done()
+
+			uc.attempt(ctx, delivery)
+			errs <- uc.repo.SaveAttempt(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attempt sends delivery and records the outcome.
+func (uc *UseCase) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	sendCtx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	timestamp := time.Now().Unix()
+	status, err := uc.sender.Send(sendCtx, delivery.Webhook.URL, map[string]string{
+		"Content-Type":  "application/json",
+		IDHeader:        delivery.EventID,
+		TimestampHeader: strconv.FormatInt(timestamp, 10),
+		SignatureHeader: Signature(delivery.Webhook.Secret, timestamp, delivery.Payload),
+	}, delivery.Payload)
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = status
+	delivery.LastError = ""
+
+	if err == nil && status >= 200 && status < 300 {
+		delivery.Status = model.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	if err != nil {
+		delivery.LastError = err.Error()
+	} else {
+		delivery.LastError = fmt.Sprintf("unexpected response status %d", status)
+	}
+
+	if delivery.Attempts >= uc.retry.Attempts {
+		delivery.Status = model.DeliveryDead
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(uc.retry.Backoff(delivery.Attempts))
+}
+
+// Backoff is the delay after the given number of failed attempts.
+func (r Retry) Backoff(attempts int) time.Duration {
+	delay := r.Base
+	for i := 1; i < attempts && delay < r.Max; i++ {
+		delay *= 2
+	}
+
+	if delay > r.Max {
+		return r.Max
+	}
+
+	return delay
+}
+
+// newWalletEvent is the wallet event of an outbox event, ok is false for
+// the events partners are not notified of.
+func newWalletEvent(event model.OutboxEvent) (walletEvent model.WalletEvent, ok bool, err error) {
+	switch event.Type {
+	case model.OutboxBalanceChanged:
+		balance := model.BalanceEvent{}
+		if err = json.Unmarshal(event.Payload, &balance); err != nil {
+			return walletEvent, false, err
+		}
+
+		eventType, notified := notifiedActions[balance.Entry.Action]
+		if !notified {
+			return walletEvent, false, nil
+		}
+
+		return model.WalletEvent{
+			Type:   eventType,
+			Wallet: model.Wallet{ID: event.WalletID, Balance: balance.Balance},
+			Entry:  &balance.Entry,
+		}, true, nil
+	case model.OutboxStatusChanged:
+		change := model.WalletStatusChange{}
+		if err = json.Unmarshal(event.Payload, &change); err != nil {
+			return walletEvent, false, err
+		}
+
+		return model.WalletEvent{
+			Type:         model.EventStatusChanged,
+			Wallet:       model.Wallet{ID: event.WalletID, Status: change.ToStatus, StatusReason: change.Reason},
+			StatusChange: &change,
+		}, true, nil
+	}
+
+	return walletEvent, false, nil
+}
+
+func randomHex(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
+
+func New(repo Repo, sender Sender, retry Retry, timeout time.Duration) *UseCase {
+	return &UseCase{
+		repo,
+		sender,
+		retry,
+		timeout,
+	}
+}